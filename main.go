@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/exec"
 
 	"github.com/noborus/ov/oviewer"
 	"github.com/spf13/cobra"
@@ -19,12 +20,14 @@ var (
 var (
 	cfgFile string
 
-	config oviewer.Config
+	config = oviewer.NewConfig()
 
 	// ver is version information.
 	ver bool
 	// helpKey is key bind information.
 	helpKey bool
+	// execCommand is exec mode.
+	execCommand bool
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -33,6 +36,7 @@ var rootCmd = &cobra.Command{
 	Short: "ov is a feature rich pager",
 	Long: `ov is a feature rich pager(such as more/less).
 It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
+With --exec, it runs the command and displays its stdout and stderr.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if ver {
@@ -52,10 +56,23 @@ It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
 			return err
 		}
 
-		ov, err := oviewer.Open(args...)
+		var ov *oviewer.Root
+		var err error
+		if execCommand {
+			if len(args) == 0 {
+				return fmt.Errorf("exec mode requires a command")
+			}
+			ov, err = oviewer.ExecCommand(exec.Command(args[0], args[1:]...))
+		} else {
+			ov, err = oviewer.Open(args...)
+		}
 		if err != nil {
 			return err
 		}
+		// The library does not save the history unless HistoryFile is set.
+		if config.HistoryFile == "" {
+			config.HistoryFile = oviewer.DefaultHistoryFile()
+		}
 		ov.SetConfig(config)
 
 		if err := ov.Run(); err != nil {
@@ -84,6 +101,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ov.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&ver, "version", "v", false, "display version information")
 	rootCmd.PersistentFlags().BoolVarP(&helpKey, "help-key", "", false, "display key bind information")
+	rootCmd.PersistentFlags().BoolVarP(&execCommand, "exec", "e", false, "exec command and display its stdout and stderr")
 
 	rootCmd.PersistentFlags().BoolVarP(&config.Status.WrapMode, "wrap", "w", true, "wrap mode")
 	_ = viper.BindPFlag("Wrap", rootCmd.PersistentFlags().Lookup("wrap"))
@@ -115,9 +133,51 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&config.Status.ColumnDelimiter, "column-delimiter", "d", ",", "column delimiter")
 	_ = viper.BindPFlag("ColumnDelimiter", rootCmd.PersistentFlags().Lookup("column-delimiter"))
 
+	rootCmd.PersistentFlags().BoolVarP(&config.Status.ColumnCSV, "column-csv", "", false, "parse the columns as CSV with quoted fields")
+	_ = viper.BindPFlag("ColumnCSV", rootCmd.PersistentFlags().Lookup("column-csv"))
+
+	rootCmd.PersistentFlags().StringVarP(&config.Status.ColumnQuote, "column-quote", "", oviewer.DefaultColumnQuote, "quote character of the CSV columns")
+	_ = viper.BindPFlag("ColumnQuote", rootCmd.PersistentFlags().Lookup("column-quote"))
+
+	rootCmd.PersistentFlags().BoolVarP(&config.Status.ColumnAlign, "column-align", "", false, "align the columns")
+	_ = viper.BindPFlag("ColumnAlign", rootCmd.PersistentFlags().Lookup("column-align"))
+
+	rootCmd.PersistentFlags().IntVarP(&config.Status.FrozenColumns, "frozen-columns", "", 0, "number of columns not scrolled horizontally")
+	_ = viper.BindPFlag("FrozenColumns", rootCmd.PersistentFlags().Lookup("frozen-columns"))
+
+	rootCmd.PersistentFlags().IntVarP(&config.Status.FrozenWidth, "frozen-width", "", 0, "width in cells not scrolled horizontally")
+	_ = viper.BindPFlag("FrozenWidth", rootCmd.PersistentFlags().Lookup("frozen-width"))
+
+	rootCmd.PersistentFlags().StringVarP(&config.Status.JSONMode, "json", "", "", "JSON mode (color, fields)")
+	_ = viper.BindPFlag("JSONMode", rootCmd.PersistentFlags().Lookup("json"))
+
+	rootCmd.PersistentFlags().StringSliceVarP(&config.Status.JSONFields, "json-fields", "", nil, "fields of JSON displayed as columns (with --json fields)")
+	_ = viper.BindPFlag("JSONFields", rootCmd.PersistentFlags().Lookup("json-fields"))
+
+	rootCmd.PersistentFlags().BoolVarP(&config.Status.DiffMode, "diff", "", false, "diff mode (without it, a diff is detected by a git header in the first 100 lines)")
+	_ = viper.BindPFlag("DiffMode", rootCmd.PersistentFlags().Lookup("diff"))
+
+	rootCmd.PersistentFlags().StringVarP(&config.Status.Syntax, "syntax", "", "", "syntax highlighting (auto, diff, go, json, yaml, sh)")
+	_ = viper.BindPFlag("Syntax", rootCmd.PersistentFlags().Lookup("syntax"))
+
+	rootCmd.PersistentFlags().StringVarP(&config.Status.SectionDelimiter, "section-delimiter", "", "", "regexp of the section headings")
+	_ = viper.BindPFlag("SectionDelimiter", rootCmd.PersistentFlags().Lookup("section-delimiter"))
+
+	rootCmd.PersistentFlags().BoolVarP(&config.Status.SectionHeader, "section-header", "", false, "pin the current section heading below the header")
+	_ = viper.BindPFlag("SectionHeader", rootCmd.PersistentFlags().Lookup("section-header"))
+
 	rootCmd.PersistentFlags().BoolVarP(&config.Status.LineNumMode, "line-number", "n", false, "line number")
 	_ = viper.BindPFlag("LineNumMode", rootCmd.PersistentFlags().Lookup("line-number"))
 
+	rootCmd.PersistentFlags().BoolVarP(&config.Status.FollowMode, "follow-mode", "f", false, "follow mode")
+	_ = viper.BindPFlag("FollowMode", rootCmd.PersistentFlags().Lookup("follow-mode"))
+
+	rootCmd.PersistentFlags().BoolVarP(&config.DisableHistory, "disable-history", "", false, "do not save the history between sessions")
+	_ = viper.BindPFlag("DisableHistory", rootCmd.PersistentFlags().Lookup("disable-history"))
+
+	rootCmd.PersistentFlags().StringVarP(&config.TabBar, "tab-bar", "", "", "display the tab bar of the documents (top, bottom)")
+	_ = viper.BindPFlag("TabBar", rootCmd.PersistentFlags().Lookup("tab-bar"))
+
 	rootCmd.PersistentFlags().BoolVarP(&config.Debug, "debug", "", false, "debug mode")
 }
 
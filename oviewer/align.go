@@ -0,0 +1,147 @@
+package oviewer
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+// splitRanges returns the ranges of s split by the delimiter.
+func splitRanges(s string, delimiter string) [][2]int {
+	var ranges [][2]int
+	start := 0
+	if delimiter != "" {
+		for {
+			i := strings.Index(s[start:], delimiter)
+			if i < 0 {
+				break
+			}
+			ranges = append(ranges, [2]int{start, start + i})
+			start += i + len(delimiter)
+		}
+	}
+	return append(ranges, [2]int{start, len(s)})
+}
+
+// columnRanges returns the ranges of the columns of the str of the line,
+// and the column number of the first range.
+func (m *Document) columnRanges(lineNum int, str string) ([][2]int, int) {
+	if !m.ColumnCSV {
+		return splitRanges(str, m.columnDelimiter()), 0
+	}
+	// The plain split is used until the state is computed.
+	st, ok := m.csvStart(lineNum)
+	if !ok {
+		return splitRanges(str, m.columnDelimiter()), 0
+	}
+	ranges, _ := m.csvParser().split(str, st)
+	return ranges, st.column
+}
+
+// columnAligned returns true if the columns are aligned.
+// ColumnAlign is applied only in column mode.
+func (m *Document) columnAligned() bool {
+	return m.ColumnMode && m.ColumnAlign
+}
+
+// separatorWidth returns the width of the separator between the aligned columns.
+// The separator is the delimiter surrounded by blanks, and a TAB is one cell.
+func (m *Document) separatorWidth() int {
+	return runewidth.StringWidth(strings.ReplaceAll(m.columnDelimiter(), "\t", " ")) + 2
+}
+
+// alignColumns returns the contents of the line with each column padded to the widths.
+// The padding is blank cells, which are not included in the string of the contents,
+// so the selected range and the search positions map back to the original text.
+func (m *Document) alignColumns(lineNum int, lc lineContents, widths []int) lineContents {
+	str, byteMap := contentsToStr(lc)
+	ranges, first := m.columnRanges(lineNum, str)
+	blank := content{width: 1, style: tcell.StyleDefault}
+
+	aligned := make(lineContents, 0, len(lc)+len(ranges)*3)
+	for i, r := range ranges {
+		start, end := byteMap[r[0]], byteMap[r[1]]
+		aligned = append(aligned, lc[start:end]...)
+		if i == len(ranges)-1 {
+			break
+		}
+
+		pad := blank
+		if end > start {
+			pad.style = lc[end-1].style
+		}
+		if c := first + i; c < len(widths) {
+			for w := end - start; w < widths[c]; w++ {
+				aligned = append(aligned, pad)
+			}
+		}
+
+		// The delimiter without the expansion of the TAB.
+		aligned = append(aligned, blank)
+		tab := false
+		for _, c := range lc[end:byteMap[ranges[i+1][0]]] {
+			if tab && c.mainc == 0 {
+				continue
+			}
+			tab = c.mainc == '\t'
+			aligned = append(aligned, c)
+		}
+		aligned = append(aligned, blank)
+	}
+	return aligned
+}
+
+// measureColumns returns the maximum width of each column
+// of the header and the lines on the screen.
+func (root *Root) measureColumns() []int {
+	m := root.Doc
+	var widths []int
+	measure := func(lineNum int) {
+		lc, err := m.lineToContents(lineNum, m.TabWidth)
+		if err != nil {
+			return
+		}
+		str, byteMap := contentsToStr(lc)
+		ranges, first := m.columnRanges(lineNum, str)
+		for i, r := range ranges {
+			c := first + i
+			for len(widths) <= c {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], byteMap[r[1]]-byteMap[r[0]])
+		}
+	}
+	for n := 0; n < m.Header; n++ {
+		measure(n)
+	}
+	for n := 0; n < root.vHight; n++ {
+		measure(m.lineNum + m.Header + n)
+	}
+	return widths
+}
+
+// alignedColumnX returns the x of the column number in the aligned columns.
+// It returns false if the column is not on the screen.
+func (root *Root) alignedColumnX(number int) (int, bool) {
+	if root.columnWidths == nil {
+		root.columnWidths = root.measureColumns()
+	}
+	if number >= len(root.columnWidths) {
+		return 0, false
+	}
+	x := 0
+	for _, w := range root.columnWidths[:number] {
+		x += w + root.Doc.separatorWidth()
+	}
+	return x, true
+}
+
+// displayContents returns the contents of the line as displayed on the screen.
+func (root *Root) displayContents(lineNum int) (lineContents, error) {
+	lc, err := root.Doc.lineToContents(lineNum, root.Doc.TabWidth)
+	if err != nil || !root.Doc.columnAligned() {
+		return lc, err
+	}
+	return root.Doc.alignColumns(lineNum, lc, root.columnWidths), nil
+}
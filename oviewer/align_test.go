@@ -0,0 +1,153 @@
+package oviewer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_splitRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		delimiter string
+		want      [][2]int
+	}{
+		{
+			name:      "comma",
+			s:         "a,bb,c",
+			delimiter: ",",
+			want:      [][2]int{{0, 1}, {2, 4}, {5, 6}},
+		},
+		{
+			name:      "empty",
+			s:         ",,",
+			delimiter: ",",
+			want:      [][2]int{{0, 0}, {1, 1}, {2, 2}},
+		},
+		{
+			name:      "multi",
+			s:         "a::b",
+			delimiter: "::",
+			want:      [][2]int{{0, 1}, {3, 4}},
+		},
+		{
+			name:      "noDelimiter",
+			s:         "abc",
+			delimiter: "",
+			want:      [][2]int{{0, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitRanges(tt.s, tt.delimiter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_alignColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		csv       bool
+		line      string
+		widths    []int
+		wantX     []int
+	}{
+		{
+			name:      "comma",
+			delimiter: ",",
+			line:      "1,ab,c",
+			widths:    []int{3, 4, 1},
+			wantX:     []int{0, 6, 13},
+		},
+		{
+			name:      "csv",
+			delimiter: ",",
+			csv:       true,
+			line:      `1,"a,b",c`,
+			widths:    []int{2, 5, 1},
+			wantX:     []int{0, 5, 13},
+		},
+		{
+			name:      "tab",
+			delimiter: `\t`,
+			line:      "1\tab\tc",
+			widths:    []int{2, 2, 1},
+			wantX:     []int{0, 5, 10},
+		},
+		{
+			name:      "wide",
+			delimiter: ",",
+			line:      "あ,b",
+			widths:    []int{4, 1},
+			wantX:     []int{0, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource([]string{tt.line}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ColumnDelimiter = tt.delimiter
+			m.ColumnCSV = tt.csv
+			lc := strToContents(tt.line, 8)
+			aligned := m.alignColumns(0, lc, tt.widths)
+
+			str, byteMap := contentsToStr(aligned)
+			if str != tt.line {
+				t.Errorf("Document.alignColumns() string = %q, want %q", str, tt.line)
+			}
+			ranges, _ := m.columnRanges(0, str)
+			for i, r := range ranges {
+				if x := byteMap[r[0]]; x != tt.wantX[i] {
+					t.Errorf("Document.alignColumns() column %d x = %d, want %d", i, x, tt.wantX[i])
+				}
+			}
+			x := 0
+			for i, w := range tt.widths {
+				if x != tt.wantX[i] {
+					t.Errorf("separatorWidth() column %d x = %d, want %d", i, x, tt.wantX[i])
+				}
+				x += w + m.separatorWidth()
+			}
+		})
+	}
+}
+
+func TestRoot_displayContents(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnMode bool
+		want       int
+	}{
+		// "1" is padded to 3 cells and "," is surrounded by blanks.
+		{name: "columnMode", columnMode: true, want: len("1,ab") + 2 + 2},
+		{name: "columnModeOff", columnMode: false, want: len("1,ab")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource([]string{"1,ab"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, err := NewOviewer(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ColumnDelimiter = ","
+			m.ColumnMode = tt.columnMode
+			m.ColumnAlign = true
+			root.columnWidths = []int{3, 2}
+			lc, err := root.displayContents(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lc) != tt.want {
+				t.Errorf("Root.displayContents() width = %d, want %d", len(lc), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package oviewer
+
+import "sync"
+
+// checkpointLines is the interval of the lines at which the state is saved.
+const checkpointLines = chunkLines
+
+// stateFunc returns the state at the start of the line after the line n
+// that starts with the state st.
+type stateFunc func(n int, st interface{}) interface{}
+
+// lineStates is the states of a parser that continue over lines,
+// such as a quoted field of CSV or a block comment.
+// Only the state at the start of every checkpointLines lines is saved,
+// and the state of a line is computed from the checkpoint before it.
+// The checkpoints far ahead of the computed ones are computed in the background,
+// so that moving to the end of a large document does not block.
+type lineStates struct {
+	// key is the parser that the states are computed with.
+	key interface{}
+	// checkpoints is the state at the start of the line i*checkpointLines.
+	checkpoints []interface{}
+	// lineNum is the last line that the state was computed for,
+	// and state is the state at the start of it.
+	// It continues the computation of the following lines.
+	lineNum int
+	state   interface{}
+	// gen is incremented when the checkpoints are discarded,
+	// and stops the background computation.
+	gen int
+	// scanning is true while the checkpoints are computed in the background.
+	scanning bool
+	// finished is true if the background computation has finished
+	// after the last call of busy.
+	finished bool
+
+	mu sync.Mutex
+}
+
+// start returns the state at the start of lineNum.
+// If the checkpoint of lineNum is far ahead of the computed ones,
+// it starts computing them in the background and returns false.
+// ready is called when the background computation is finished,
+// and it stops when stop is closed.
+func (s *lineStates) start(key interface{}, initial interface{}, lineNum int, end func() int, next stateFunc, ready func(), stop <-chan struct{}) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.checkpoints == nil || key != s.key {
+		s.resetAll(key, initial)
+	}
+	lineNum = min(lineNum, end())
+	if lineNum <= 0 {
+		return initial, true
+	}
+	c := lineNum / checkpointLines
+	// Only one chunk is computed here.
+	if c > len(s.checkpoints) {
+		if !s.scanning {
+			s.scanning = true
+			go s.scan(s.gen, end, next, ready, stop)
+		}
+		return nil, false
+	}
+
+	from := (len(s.checkpoints) - 1) * checkpointLines
+	if c < len(s.checkpoints) {
+		from = c * checkpointLines
+	}
+	st := s.checkpoints[from/checkpointLines]
+	if s.lineNum >= from && s.lineNum <= lineNum {
+		from, st = s.lineNum, s.state
+	}
+	for n := from; n < lineNum; n++ {
+		st = next(n, st)
+		s.addCheckpoint(n+1, st)
+	}
+	s.lineNum, s.state = lineNum, st
+	return st, true
+}
+
+// addCheckpoint saves the state at the start of the line n if it is the next checkpoint.
+func (s *lineStates) addCheckpoint(n int, st interface{}) {
+	if n == len(s.checkpoints)*checkpointLines {
+		s.checkpoints = append(s.checkpoints, st)
+	}
+}
+
+// scan computes the checkpoints up to the end in the background.
+func (s *lineStates) scan(gen int, end func() int, next stateFunc, ready func(), stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		s.mu.Lock()
+		if s.gen != gen {
+			s.mu.Unlock()
+			return
+		}
+		from := (len(s.checkpoints) - 1) * checkpointLines
+		if from+checkpointLines > end() {
+			s.scanning = false
+			s.finished = true
+			s.mu.Unlock()
+			ready()
+			return
+		}
+		st := s.checkpoints[len(s.checkpoints)-1]
+		for n := from; n < from+checkpointLines; n++ {
+			st = next(n, st)
+		}
+		s.addCheckpoint(from+checkpointLines, st)
+		s.mu.Unlock()
+	}
+}
+
+// resetAll discards all states.
+func (s *lineStates) resetAll(key interface{}, initial interface{}) {
+	s.key = key
+	s.checkpoints = []interface{}{initial}
+	s.lineNum, s.state = 0, initial
+	s.gen++
+	s.scanning = false
+}
+
+// reset discards the states after the line n.
+func (s *lineStates) reset(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 {
+		s.checkpoints = nil
+		s.gen++
+		s.scanning = false
+		return
+	}
+	if s.lineNum > n {
+		s.lineNum = -1
+	}
+	if keep := n/checkpointLines + 1; keep < len(s.checkpoints) {
+		s.checkpoints = s.checkpoints[:keep]
+		s.gen++
+		s.scanning = false
+	}
+}
+
+// busy returns true while the checkpoints are computed in the background,
+// and once after they are computed, so that the lines are drawn again.
+func (s *lineStates) busy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	busy := s.scanning || s.finished
+	s.finished = false
+	return busy
+}
+
+// backgroundScan scans an index of the lines, such as the headers of a diff,
+// in the background when the lines to scan are far ahead of the scanned ones,
+// so that drawing the end of a large document does not block.
+// The index and backgroundScan are protected by the mutex of the index.
+type backgroundScan struct {
+	// scanning is true while the lines are scanned in the background.
+	scanning bool
+	// finished is true if the background scan has finished after the last call of busy.
+	finished bool
+	// target is the line that the background scan continues up to.
+	target int
+	// gen is incremented when the index is discarded,
+	// and stops the background scan.
+	gen int
+}
+
+// start scans the lines before lineNum with scanTo.
+// scanned is the number of the scanned lines.
+// If lineNum is more than one chunk ahead of it,
+// it starts scanning in the background and returns false.
+// It must be called with mu locked, and the background scan locks mu for each chunk.
+// It stops when stop is closed.
+func (b *backgroundScan) start(mu *sync.Mutex, scanned int, lineNum int, scanTo func(int), stop <-chan struct{}) bool {
+	if lineNum <= scanned+checkpointLines {
+		scanTo(lineNum)
+		return true
+	}
+	b.target = max(b.target, lineNum)
+	if !b.scanning {
+		b.scanning = true
+		go b.scan(mu, b.gen, scanned, scanTo, stop)
+	}
+	return false
+}
+
+// scan scans the lines from the line from up to the target one chunk at a time.
+func (b *backgroundScan) scan(mu *sync.Mutex, gen int, from int, scanTo func(int), stop <-chan struct{}) {
+	for n := from + checkpointLines; ; n += checkpointLines {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		mu.Lock()
+		if b.gen != gen {
+			mu.Unlock()
+			return
+		}
+		if n >= b.target {
+			scanTo(b.target)
+			b.scanning = false
+			b.finished = true
+			mu.Unlock()
+			return
+		}
+		scanTo(n)
+		mu.Unlock()
+	}
+}
+
+// busy returns true while the lines are scanned in the background,
+// and once after they are scanned, so that the lines are drawn again.
+// It must be called with the mutex of the index locked.
+func (b *backgroundScan) busy() bool {
+	busy := b.scanning || b.finished
+	b.finished = false
+	return busy
+}
+
+// reset stops the background scan when the index is discarded.
+func (b *backgroundScan) reset() {
+	b.gen++
+	b.scanning = false
+	b.target = 0
+}
@@ -0,0 +1,56 @@
+package oviewer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLineStates_start(t *testing.T) {
+	total := checkpointLines * 5
+	end := func() int { return total }
+	// The state is the number of the lines before the line.
+	next := func(n int, st interface{}) interface{} {
+		return st.(int) + 1
+	}
+	ready := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	s := &lineStates{}
+	start := func(lineNum int) (interface{}, bool) {
+		return s.start("key", 0, lineNum, end, next, func() { close(ready) }, stop)
+	}
+
+	for _, n := range []int{0, 10, 5, checkpointLines + 10, checkpointLines - 1} {
+		if got, ok := start(n); !ok || got != n {
+			t.Errorf("lineStates.start(%d) = %v, %t, want %d, true", n, got, ok, n)
+		}
+	}
+	// The far lines are computed in the background.
+	far := checkpointLines*4 + 10
+	if _, ok := start(far); ok {
+		t.Errorf("lineStates.start(%d) = true, want false", far)
+	}
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatal("lineStates did not finish scanning")
+	}
+	// It is busy once more after the scan so that the lines are drawn again.
+	if !s.busy() {
+		t.Error("lineStates.busy() after the scan = false, want true")
+	}
+	if s.busy() {
+		t.Error("lineStates.busy() = true, want false")
+	}
+	if got, ok := start(far); !ok || got != far {
+		t.Errorf("lineStates.start(%d) = %v, %t, want %d, true", far, got, ok, far)
+	}
+
+	s.reset(checkpointLines + 1)
+	if got := len(s.checkpoints); got != 2 {
+		t.Errorf("len(lineStates.checkpoints) = %d, want 2", got)
+	}
+	if got, ok := start(checkpointLines*2 + 1); !ok || got != checkpointLines*2+1 {
+		t.Errorf("lineStates.start(%d) = %v, %t", checkpointLines*2+1, got, ok)
+	}
+}
@@ -22,6 +22,12 @@ type content struct {
 // lineContents represents one line of contents.
 type lineContents []content
 
+// copy returns a copy of the contents to be changed for drawing.
+// Copy so as not to change the cached contents, which are shared by the panes.
+func (lc lineContents) copy() lineContents {
+	return append(lineContents(nil), lc...)
+}
+
 // The states of the ANSI escape code parser.
 const (
 	ansiText = iota
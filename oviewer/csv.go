@@ -0,0 +1,151 @@
+package oviewer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultColumnQuote is the default quote character of the CSV parsing.
+const DefaultColumnQuote = `"`
+
+// csvState is the state of the CSV parser at the start of a line.
+type csvState struct {
+	// quoted is true if the line starts in a quoted field of the previous line.
+	quoted bool
+	// column is the column number at the start of the line.
+	column int
+}
+
+// csvParser splits a line into columns like CSV or TSV.
+// A field that starts with the quote can contain the delimiter and newlines,
+// and the quote in it is escaped by doubling it or by the escape.
+type csvParser struct {
+	delimiter string
+	quote     string
+	escape    string
+}
+
+// split returns the ranges of the columns of the line
+// and the state at the start of the next line.
+// The first range is the column st.column.
+func (p csvParser) split(s string, st csvState) ([][2]int, csvState) {
+	var ranges [][2]int
+	column := st.column
+	quoted := st.quoted
+	start := 0
+	// blank is true while only spaces are in the field.
+	blank := !quoted
+	for i := 0; i < len(s); {
+		if p.escape != "" && strings.HasPrefix(s[i:], p.escape) && i+len(p.escape) < len(s) {
+			i += len(p.escape)
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			blank = false
+			continue
+		}
+		if quoted {
+			if strings.HasPrefix(s[i:], p.quote) {
+				i += len(p.quote)
+				if strings.HasPrefix(s[i:], p.quote) {
+					i += len(p.quote)
+					continue
+				}
+				quoted = false
+				continue
+			}
+			i++
+			continue
+		}
+		if blank && p.quote != "" && strings.HasPrefix(s[i:], p.quote) {
+			quoted = true
+			blank = false
+			i += len(p.quote)
+			continue
+		}
+		if p.delimiter != "" && strings.HasPrefix(s[i:], p.delimiter) {
+			ranges = append(ranges, [2]int{start, i})
+			i += len(p.delimiter)
+			start = i
+			column++
+			blank = true
+			continue
+		}
+		if s[i] != ' ' {
+			blank = false
+		}
+		i++
+	}
+	ranges = append(ranges, [2]int{start, len(s)})
+
+	if quoted {
+		return ranges, csvState{quoted: true, column: column}
+	}
+	return ranges, csvState{}
+}
+
+// columnRange returns the range of the column number in the line
+// that starts with the state.
+// It returns -1, -1 if the line does not have the column.
+func (p csvParser) columnRange(s string, st csvState, number int) (int, int) {
+	ranges, _ := p.split(s, st)
+	n := number - st.column
+	if n < 0 || n >= len(ranges) {
+		return -1, -1
+	}
+	return ranges[n][0], ranges[n][1]
+}
+
+// columnDelimiter returns the delimiter of the column mode.
+// "\t" is replaced with a TAB so that TSV can be specified.
+func (m *Document) columnDelimiter() string {
+	return strings.ReplaceAll(m.ColumnDelimiter, `\t`, "\t")
+}
+
+// csvParser returns the parser of the document settings.
+func (m *Document) csvParser() csvParser {
+	quote := m.ColumnQuote
+	if quote == "" {
+		quote = DefaultColumnQuote
+	}
+	return csvParser{
+		delimiter: m.columnDelimiter(),
+		quote:     quote,
+		escape:    m.ColumnEscape,
+	}
+}
+
+// csvStart returns the state of the CSV parser at the start of the line.
+// The states are computed from the first line,
+// because a quoted field can continue over lines.
+// It returns false if the state is not computed yet.
+func (m *Document) csvStart(lineNum int) (csvState, bool) {
+	p := m.csvParser()
+	next := func(n int, st interface{}) interface{} {
+		_, next := p.split(m.viewLine(n), st.(csvState))
+		return next
+	}
+	st, ok := m.csvStates.start(p, csvState{}, lineNum, m.BufEndNum, next, func() {}, m.done)
+	if !ok {
+		return csvState{}, false
+	}
+	return st.(csvState), true
+}
+
+// resetCSV discards the cached states after the line n.
+func (m *Document) resetCSV(n int) {
+	m.csvStates.reset(n)
+}
+
+// columnRange returns the range of the column number of the str of the line.
+// The columns are split by the delimiter string, or parsed as CSV if ColumnCSV is true.
+func (m *Document) columnRange(lineNum int, str string, number int) (int, int) {
+	if !m.ColumnCSV {
+		return rangePosition(str, m.columnDelimiter(), number)
+	}
+	// The plain split is used until the state is computed.
+	st, ok := m.csvStart(lineNum)
+	if !ok {
+		return rangePosition(str, m.columnDelimiter(), number)
+	}
+	return m.csvParser().columnRange(str, st, number)
+}
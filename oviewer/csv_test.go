@@ -0,0 +1,133 @@
+package oviewer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_csvParser_split(t *testing.T) {
+	type args struct {
+		s  string
+		st csvState
+	}
+	tests := []struct {
+		name       string
+		parser     csvParser
+		args       args
+		wantRanges [][2]int
+		wantNext   csvState
+	}{
+		{
+			name:       "plain",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: "a,bb,c"},
+			wantRanges: [][2]int{{0, 1}, {2, 4}, {5, 6}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "quotedComma",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `a,"b,c",d`},
+			wantRanges: [][2]int{{0, 1}, {2, 7}, {8, 9}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "doubledQuote",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `"a"",b",c`},
+			wantRanges: [][2]int{{0, 7}, {8, 9}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "quoteInField",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `a"b,c`},
+			wantRanges: [][2]int{{0, 3}, {4, 5}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "escape",
+			parser:     csvParser{delimiter: ",", quote: `"`, escape: `\`},
+			args:       args{s: `"a\",b",c\,d`},
+			wantRanges: [][2]int{{0, 7}, {8, 12}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "singleQuote",
+			parser:     csvParser{delimiter: "\t", quote: "'"},
+			args:       args{s: "a\t'b\tc'\td"},
+			wantRanges: [][2]int{{0, 1}, {2, 7}, {8, 9}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "openQuote",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `a,"b,c`},
+			wantRanges: [][2]int{{0, 1}, {2, 6}},
+			wantNext:   csvState{quoted: true, column: 1},
+		},
+		{
+			name:       "continued",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `d,e",f`, st: csvState{quoted: true, column: 1}},
+			wantRanges: [][2]int{{0, 4}, {5, 6}},
+			wantNext:   csvState{},
+		},
+		{
+			name:       "stillQuoted",
+			parser:     csvParser{delimiter: ",", quote: `"`},
+			args:       args{s: `d,e`, st: csvState{quoted: true, column: 2}},
+			wantRanges: [][2]int{{0, 3}},
+			wantNext:   csvState{quoted: true, column: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRanges, gotNext := tt.parser.split(tt.args.s, tt.args.st)
+			if !reflect.DeepEqual(gotRanges, tt.wantRanges) {
+				t.Errorf("csvParser.split() got = %v, want %v", gotRanges, tt.wantRanges)
+			}
+			if gotNext != tt.wantNext {
+				t.Errorf("csvParser.split() got1 = %v, want %v", gotNext, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestDocument_columnRange(t *testing.T) {
+	lines := []string{
+		`id,name,note`,
+		`1,"Smith, John","multi`,
+		`line, note"`,
+		`2,Doe,x`,
+	}
+	tests := []struct {
+		name    string
+		csv     bool
+		lineNum int
+		number  int
+		wantS   int
+		wantE   int
+	}{
+		{name: "plain", csv: false, lineNum: 1, number: 2, wantS: 9, wantE: 15},
+		{name: "csv", csv: true, lineNum: 1, number: 1, wantS: 2, wantE: 15},
+		{name: "csvLast", csv: true, lineNum: 1, number: 2, wantS: 16, wantE: 22},
+		{name: "continued", csv: true, lineNum: 2, number: 2, wantS: 0, wantE: 11},
+		{name: "notInLine", csv: true, lineNum: 2, number: 0, wantS: -1, wantE: -1},
+		{name: "next", csv: true, lineNum: 3, number: 1, wantS: 2, wantE: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource(lines))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ColumnDelimiter = ","
+			m.ColumnCSV = tt.csv
+			gotS, gotE := m.columnRange(tt.lineNum, lines[tt.lineNum], tt.number)
+			if gotS != tt.wantS || gotE != tt.wantE {
+				t.Errorf("Document.columnRange() = %v, %v, want %v, %v", gotS, gotE, tt.wantS, tt.wantE)
+			}
+		})
+	}
+}
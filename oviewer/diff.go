@@ -0,0 +1,359 @@
+package oviewer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// diffFileHeader is the prefix of the header of a file of git diff.
+	diffFileHeader = "diff --git "
+	// diffHunkHeader is the prefix of the header of a hunk.
+	diffHunkHeader = "@@"
+	// diffDetectLines is the number of the lines to detect a diff.
+	diffDetectLines = 100
+)
+
+// diffFile is a file of a diff.
+type diffFile struct {
+	// line is the line of the file header.
+	line int
+	// name is the file name of the new side.
+	name string
+	// added is the number of the added lines.
+	added int
+	// removed is the number of the removed lines.
+	removed int
+}
+
+// diffIndex is the positions of the files and the hunks of a diff.
+type diffIndex struct {
+	files []diffFile
+	hunks []int
+	// end is the number of the scanned lines.
+	end int
+	// scan scans the lines in the background for the status line.
+	scan backgroundScan
+}
+
+// add adds the line n to the index.
+func (idx *diffIndex) add(n int, line string) {
+	idx.end = n + 1
+	switch {
+	case strings.HasPrefix(line, diffFileHeader):
+		idx.files = append(idx.files, diffFile{line: n, name: diffFileName(line)})
+	case strings.HasPrefix(line, diffHunkHeader):
+		idx.hunks = append(idx.hunks, n)
+	case len(idx.files) > 0 && idx.inHunk():
+		f := &idx.files[len(idx.files)-1]
+		if strings.HasPrefix(line, "+") {
+			f.added++
+		} else if strings.HasPrefix(line, "-") {
+			f.removed++
+		}
+	}
+}
+
+// inHunk returns true if the last header is a hunk of the last file.
+func (idx *diffIndex) inHunk() bool {
+	return len(idx.hunks) > 0 && idx.hunks[len(idx.hunks)-1] > idx.files[len(idx.files)-1].line
+}
+
+// diffFileName returns the file name of the header "diff --git a/name b/name".
+func diffFileName(line string) string {
+	s := strings.TrimPrefix(line, diffFileHeader)
+	if i := strings.LastIndex(s, " b/"); i >= 0 {
+		return s[i+3:]
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// hunkRange returns the range part ("@@ -1,2 +1,3 @@") of the hunk header.
+func hunkRange(line string) string {
+	i := strings.Index(line[len(diffHunkHeader):], diffHunkHeader)
+	if i < 0 {
+		return line
+	}
+	return line[:i+len(diffHunkHeader)*2]
+}
+
+// plainLine returns the line without escape sequences.
+func (m *Document) plainLine(n int) string {
+	line := m.GetLine(n)
+	if strings.ContainsAny(line, "\x1b\b") {
+		line = stripEscapeSequence.ReplaceAllString(line, "")
+	}
+	return line
+}
+
+// scanDiff scans the lines before lineNum into the index.
+func (m *Document) scanDiff(lineNum int) {
+	end := min(lineNum, m.BufEndNum())
+	for n := m.diff.end; n < end; n++ {
+		m.diff.add(n, m.plainLine(n))
+	}
+}
+
+// resetDiff discards the index if the line n has been scanned.
+func (m *Document) resetDiff(n int) {
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	if n < m.diff.end {
+		m.diff.scan.reset()
+		m.diff = diffIndex{scan: m.diff.scan}
+	}
+}
+
+// diffMode returns true if the document is a diff.
+// It is forced by DiffMode, and detected by the syntax
+// or the file header in the first lines.
+func (m *Document) diffMode() bool {
+	if m.DiffMode || m.lexer() == (diffLexer{}) {
+		return true
+	}
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	m.scanDiff(diffDetectLines)
+	return len(m.diff.files) > 0 && m.diff.files[0].line < diffDetectLines
+}
+
+// diffLines returns the lines of the hunk headers, or the file headers if hunk is false.
+func (m *Document) diffLines(hunk bool) []int {
+	if hunk {
+		return m.diff.hunks
+	}
+	lines := make([]int, len(m.diff.files))
+	for i, f := range m.diff.files {
+		lines[i] = f.line
+	}
+	return lines
+}
+
+// lastDiffLine returns the last scanned file header (or hunk header).
+func (m *Document) lastDiffLine(hunk bool) int {
+	if hunk {
+		if len(m.diff.hunks) == 0 {
+			return -1
+		}
+		return m.diff.hunks[len(m.diff.hunks)-1]
+	}
+	if len(m.diff.files) == 0 {
+		return -1
+	}
+	return m.diff.files[len(m.diff.files)-1].line
+}
+
+// nextDiffLine returns the nearest file header (or hunk header) after (or before) lineNum.
+func (m *Document) nextDiffLine(lineNum int, hunk bool, forward bool) (int, bool) {
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	if forward {
+		m.scanDiff(lineNum + 1)
+		// Scan only until the next header is found.
+		for m.diff.end < m.BufEndNum() && m.lastDiffLine(hunk) <= lineNum {
+			m.scanDiff(m.diff.end + 1)
+		}
+		lines := m.diffLines(hunk)
+		i := sort.SearchInts(lines, lineNum+1)
+		if i < len(lines) {
+			return lines[i], true
+		}
+		return 0, false
+	}
+	m.scanDiff(lineNum)
+	lines := m.diffLines(hunk)
+	i := sort.SearchInts(lines, lineNum)
+	if i > 0 {
+		return lines[i-1], true
+	}
+	return 0, false
+}
+
+// diffPosition returns the file and the hunk header of lineNum.
+// hunk is -1 if lineNum is not in a hunk.
+// It returns false if lineNum is not in a file,
+// or if the lines up to lineNum are being scanned in the background.
+func (m *Document) diffPosition(lineNum int) (diffFile, int, bool) {
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	if !m.diff.scan.start(&m.diffMu, m.diff.end, min(lineNum+1, m.BufEndNum()), m.scanDiff, m.done) {
+		return diffFile{}, -1, false
+	}
+	if lineNum >= m.diff.end {
+		return diffFile{}, -1, false
+	}
+	files := m.diff.files
+	i := sort.Search(len(files), func(i int) bool { return files[i].line > lineNum })
+	if i == 0 {
+		return diffFile{}, -1, false
+	}
+	file := files[i-1]
+	hunks := m.diff.hunks
+	j := sort.Search(len(hunks), func(j int) bool { return hunks[j] > lineNum })
+	if j == 0 || hunks[j-1] < file.line {
+		return file, -1, true
+	}
+	return file, hunks[j-1], true
+}
+
+// scanningDiff returns true while the lines are scanned in the background,
+// and once after they are scanned.
+func (m *Document) scanningDiff() bool {
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	return m.diff.scan.busy()
+}
+
+// diffFiles returns all files of the diff.
+func (m *Document) diffFiles() []diffFile {
+	m.diffMu.Lock()
+	defer m.diffMu.Unlock()
+	m.scanDiff(m.BufEndNum())
+	files := make([]diffFile, len(m.diff.files))
+	copy(files, m.diff.files)
+	return files
+}
+
+// diffStatus returns the current file and hunk of the diff for the status line.
+// It returns an empty string while the lines far ahead are scanned in the background,
+// and the status is updated when the scan is finished.
+func (m *Document) diffStatus() string {
+	if !m.diffMode() {
+		return ""
+	}
+	file, hunk, ok := m.diffPosition(m.lineNum + m.Header)
+	if !ok {
+		return ""
+	}
+	if hunk < 0 {
+		return file.name
+	}
+	return file.name + " " + hunkRange(m.plainLine(hunk))
+}
+
+// nextDiffFile moves to the next file of the diff.
+func (root *Root) nextDiffFile() {
+	root.moveDiff(false, true)
+}
+
+// previousDiffFile moves to the previous file of the diff.
+func (root *Root) previousDiffFile() {
+	root.moveDiff(false, false)
+}
+
+// nextHunk moves to the next hunk of the diff.
+func (root *Root) nextHunk() {
+	root.moveDiff(true, true)
+}
+
+// previousHunk moves to the previous hunk of the diff.
+func (root *Root) previousHunk() {
+	root.moveDiff(true, false)
+}
+
+func (root *Root) moveDiff(hunk bool, forward bool) {
+	m := root.Doc
+	if !m.diffMode() {
+		root.setMessage(ErrNotDiffMode.Error())
+		return
+	}
+	name := "file"
+	if hunk {
+		name = "hunk"
+	}
+	lineNum, ok := m.nextDiffLine(m.lineNum+m.Header, hunk, forward)
+	if !ok {
+		root.setMessage(fmt.Sprintf("%s %s", name, ErrNotFound))
+		return
+	}
+	root.moveLine(lineNum - m.Header)
+	root.setMessage(fmt.Sprintf("Moved to %s", name))
+}
+
+// diffFileList is to switch between the file list of the diff and normal screen.
+func (root *Root) diffFileList() {
+	if root.input.mode == DiffList {
+		root.toNormal()
+		return
+	}
+	m := root.DocList[root.CurrentDoc]
+	if !m.diffMode() {
+		root.setMessage(ErrNotDiffMode.Error())
+		return
+	}
+	files := m.diffFiles()
+	doc, err := NewDiffFileListDoc(m, files)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	cur, _, _ := m.diffPosition(m.lineNum + m.Header)
+	n := sort.Search(len(files), func(i int) bool { return files[i].line >= cur.line })
+	root.diffListFiles = files
+	root.setDocument(doc)
+	root.input.mode = DiffList
+	root.moveListCursor(n, len(files))
+}
+
+// diffListKey handles the keys of the file list of the diff.
+// Up and Down move the cursor, and Enter jumps to the file at the cursor.
+// It returns false if the key is not handled.
+func (root *Root) diffListKey(ev *tcell.EventKey) bool {
+	n := root.listCursor
+	switch ev.Key() {
+	case tcell.KeyUp:
+		root.moveListCursor(n-1, len(root.diffListFiles))
+	case tcell.KeyDown:
+		root.moveListCursor(n+1, len(root.diffListFiles))
+	case tcell.KeyEnter:
+		root.jumpDiffFile(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// clickDiffList jumps to the file of the list at y.
+func (root *Root) clickDiffList(y int) {
+	if n, ok := root.listEntryAt(y); ok {
+		root.jumpDiffFile(n)
+	}
+}
+
+// jumpDiffFile returns to the diff and moves to the file n of the list.
+func (root *Root) jumpDiffFile(n int) {
+	if n < 0 || n >= len(root.diffListFiles) {
+		return
+	}
+	file := root.diffListFiles[n]
+	root.toNormal()
+	root.moveLine(file.line - root.Doc.Header)
+	root.setMessage(fmt.Sprintf("Moved to file %s", file.name))
+}
+
+// NewDiffFileListDoc generates a document that lists the files of the diff.
+func NewDiffFileListDoc(m *Document, files []diffFile) (*Document, error) {
+	doc, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	doc.FileName = fmt.Sprintf("Files [%s] (Enter:select)", m.FileName)
+	doc.Header = 1
+	lines := []string{"   no     line   added removed  file"}
+	for n, f := range files {
+		lines = append(lines, fmt.Sprintf(" %4d  %7d  %6s  %6s  %s",
+			n+1, m.lineNumber(f.line)-m.Header+1,
+			fmt.Sprintf("+%d", f.added), fmt.Sprintf("-%d", f.removed), f.name))
+	}
+	src := NewSliceSource(lines)
+	src.SetEOF(true)
+	doc.SetSource(src)
+	return doc, nil
+}
@@ -0,0 +1,213 @@
+package oviewer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testDiff = []string{
+	"commit 1234567",
+	"",
+	"diff --git a/main.go b/main.go",
+	"index 1111111..2222222 100644",
+	"--- a/main.go",
+	"+++ b/main.go",
+	"@@ -1,3 +1,4 @@ package main",
+	" context",
+	"+added",
+	"-removed",
+	"@@ -10,2 +11,3 @@",
+	"+added",
+	"\x1b[1mdiff --git a/dir/b c.go b/dir/b c.go\x1b[m",
+	"--- a/dir/b c.go",
+	"+++ b/dir/b c.go",
+	"@@ -5 +5 @@",
+	"--- removed",
+}
+
+func Test_diffFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "git", line: "diff --git a/main.go b/main.go", want: "main.go"},
+		{name: "space", line: "diff --git a/b c.go b/b c.go", want: "b c.go"},
+		{name: "noPrefix", line: "diff --git x.go y.go", want: "y.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffFileName(tt.line); got != tt.want {
+				t.Errorf("diffFileName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hunkRange(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "context", line: "@@ -1,3 +1,4 @@ func main() {", want: "@@ -1,3 +1,4 @@"},
+		{name: "noContext", line: "@@ -5 +5 @@", want: "@@ -5 +5 @@"},
+		{name: "broken", line: "@@ -5", want: "@@ -5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hunkRange(tt.line); got != tt.want {
+				t.Errorf("hunkRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_diffFiles(t *testing.T) {
+	m, err := NewSourceDocument(NewSliceSource(testDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.diffMode() {
+		t.Fatalf("Document.diffMode() = false, want true")
+	}
+	want := []diffFile{
+		{line: 2, name: "main.go", added: 2, removed: 1},
+		{line: 12, name: "dir/b c.go", added: 0, removed: 1},
+	}
+	if got := m.diffFiles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.diffFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestDocument_nextDiffLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		lineNum int
+		hunk    bool
+		forward bool
+		want    int
+		wantOK  bool
+	}{
+		{name: "nextFile", lineNum: 0, forward: true, want: 2, wantOK: true},
+		{name: "nextFileFromHeader", lineNum: 2, forward: true, want: 12, wantOK: true},
+		{name: "noNextFile", lineNum: 12, forward: true, wantOK: false},
+		{name: "previousFile", lineNum: 12, forward: false, want: 2, wantOK: true},
+		{name: "noPreviousFile", lineNum: 2, forward: false, wantOK: false},
+		{name: "nextHunk", lineNum: 6, hunk: true, forward: true, want: 10, wantOK: true},
+		{name: "previousHunk", lineNum: 11, hunk: true, forward: false, want: 10, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource(testDiff))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := m.nextDiffLine(tt.lineNum, tt.hunk, tt.forward)
+			if ok != tt.wantOK {
+				t.Errorf("Document.nextDiffLine() ok = %v, want %v", ok, tt.wantOK)
+				return
+			}
+			if ok && got != tt.want {
+				t.Errorf("Document.nextDiffLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_nextDiffLine_scan(t *testing.T) {
+	m, err := NewSourceDocument(NewSliceSource(testDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := m.nextDiffLine(0, false, true)
+	if !ok {
+		t.Fatal("Document.nextDiffLine() ok = false, want true")
+	}
+	// The lines after the found header are not scanned.
+	if m.diff.end != got+1 {
+		t.Errorf("Document.nextDiffLine() scanned %d lines, want %d", m.diff.end, got+1)
+	}
+}
+
+func TestDocument_diffStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		lineNum int
+		want    string
+	}{
+		{name: "beforeFiles", lineNum: 0, want: ""},
+		{name: "fileHeader", lineNum: 4, want: "main.go"},
+		{name: "hunk", lineNum: 8, want: "main.go @@ -1,3 +1,4 @@"},
+		{name: "secondHunk", lineNum: 11, want: "main.go @@ -10,2 +11,3 @@"},
+		{name: "secondFile", lineNum: 13, want: "dir/b c.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource(testDiff))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.lineNum = tt.lineNum
+			if got := m.diffStatus(); got != tt.want {
+				t.Errorf("Document.diffStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_diffStatus_far(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines int
+	}{
+		{name: "afterDetectLines", lines: diffDetectLines * 3},
+		{name: "background", lines: checkpointLines * 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := []string{"diff --git a/f0 b/f0", "@@ -1,1 +1,1 @@"}
+			for len(lines) < tt.lines {
+				lines = append(lines, "+line")
+			}
+			lines = append(lines, "diff --git a/f1 b/f1", "@@ -2,1 +2,1 @@", "+last")
+			m, err := NewSourceDocument(NewSliceSource(lines))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[int]string{
+				10:           "f0 @@ -1,1 +1,1 @@",
+				tt.lines - 1: "f0 @@ -1,1 +1,1 @@",
+				tt.lines + 2: "f1 @@ -2,1 +2,1 @@",
+			}
+			for lineNum, w := range want {
+				m.lineNum = lineNum
+				got := m.diffStatus()
+				for i := 0; i < 100 && m.scanningStates(); i++ {
+					time.Sleep(10 * time.Millisecond)
+				}
+				if got == "" {
+					got = m.diffStatus()
+				}
+				if got != w {
+					t.Errorf("Document.diffStatus() at %d = %v, want %v", lineNum, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestDocument_diffMode(t *testing.T) {
+	m, err := NewSourceDocument(NewSliceSource([]string{"plain", "text"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.diffMode() {
+		t.Errorf("Document.diffMode() = true, want false")
+	}
+	m.DiffMode = true
+	if !m.diffMode() {
+		t.Errorf("Document.diffMode() = false, want true")
+	}
+}
@@ -0,0 +1,157 @@
+package oviewer
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+)
+
+// switchDocument switches to the document n of DocList.
+func (root *Root) switchDocument(n int) {
+	if n < 0 || n >= len(root.DocList) {
+		return
+	}
+	root.CurrentDoc = n
+	root.setDocument(root.DocList[n])
+	root.input.mode = Normal
+}
+
+// closeDocument removes the document n from DocList and closes it.
+// The last document cannot be closed.
+func (root *Root) closeDocument(n int) error {
+	if len(root.DocList) <= 1 {
+		return ErrLastDocument
+	}
+	if n < 0 || n >= len(root.DocList) {
+		return ErrOutOfRange
+	}
+	m := root.DocList[n]
+	root.DocList = append(root.DocList[:n], root.DocList[n+1:]...)
+	if root.CurrentDoc > n || root.CurrentDoc >= len(root.DocList) {
+		root.CurrentDoc--
+	}
+	root.replaceDocument(m, min(n, len(root.DocList)-1))
+	root.saveFilePosition(m)
+	m.close()
+	return nil
+}
+
+// docList is to switch between the document list screen and normal screen.
+func (root *Root) docList() {
+	if root.input.mode == DocList {
+		root.toNormal()
+		return
+	}
+	root.toDocList(root.CurrentDoc)
+}
+
+// toDocList displays the document list with the cursor on the entry n.
+func (root *Root) toDocList(n int) {
+	doc, err := NewDocListDoc(root.DocList, root.CurrentDoc)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.setDocument(doc)
+	root.input.mode = DocList
+	root.moveDocListCursor(n)
+}
+
+// moveDocListCursor moves the cursor of the document list to the entry n.
+func (root *Root) moveDocListCursor(n int) {
+	root.moveListCursor(n, len(root.DocList))
+}
+
+// moveListCursor moves the cursor of the list of count entries to the entry n,
+// and scrolls the list so that the cursor is visible.
+func (root *Root) moveListCursor(n int, count int) {
+	n = max(min(n, count-1), 0)
+	root.listCursor = n
+	m := root.Doc
+	if n < m.lineNum {
+		root.moveLine(n)
+	} else if n+m.Header > root.bottomPos {
+		root.moveLine(m.lineNum + n + m.Header - root.bottomPos)
+	}
+}
+
+// docListKey handles the keys of the document list screen.
+// Up and Down move the cursor, Enter switches to the document
+// at the cursor, and x or Delete closes it.
+// It returns false if the key is not handled.
+func (root *Root) docListKey(ev *tcell.EventKey) bool {
+	n := root.listCursor
+	switch {
+	case ev.Key() == tcell.KeyUp:
+		root.moveDocListCursor(n - 1)
+	case ev.Key() == tcell.KeyDown:
+		root.moveDocListCursor(n + 1)
+	case ev.Key() == tcell.KeyEnter:
+		root.switchDocument(n)
+	case ev.Key() == tcell.KeyDelete, ev.Key() == tcell.KeyRune && ev.Rune() == 'x':
+		if err := root.closeDocument(n); err != nil {
+			root.setMessage(err.Error())
+			return true
+		}
+		root.toDocList(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// drawListCursor reverses the line of the cursor of the list.
+func (root *Root) drawListCursor() {
+	line := root.listCursor + root.Doc.Header
+	for y, l := range root.lnumber {
+		if l.line != line || y >= root.statusPos {
+			continue
+		}
+		for x := 0; x < root.vWidth; x++ {
+			r, c, style, _ := root.GetContent(x, y)
+			root.SetContent(x, y, r, c, style.Reverse(true))
+		}
+	}
+}
+
+// clickDocList switches to the document of the list at y.
+func (root *Root) clickDocList(y int) {
+	if n, ok := root.listEntryAt(y); ok && n < len(root.DocList) {
+		root.switchDocument(n)
+	}
+}
+
+// listEntryAt returns the entry of the list at y.
+func (root *Root) listEntryAt(y int) (int, bool) {
+	if y < 0 || y >= len(root.lnumber) || root.lnumber[y].line < root.Doc.Header {
+		return 0, false
+	}
+	return root.lnumber[y].line - root.Doc.Header, true
+}
+
+// NewDocListDoc generates a document that lists the documents.
+// current is marked with "*".
+func NewDocListDoc(docs []*Document, current int) (*Document, error) {
+	doc, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	doc.FileName = "Documents (Enter:select x:close)"
+	doc.Header = 1
+	lines := []string{"   no    lines     name"}
+	for n, m := range docs {
+		cur := " "
+		if n == current {
+			cur = "*"
+		}
+		more := ""
+		if !m.BufEOF() {
+			more = "..."
+		}
+		lines = append(lines, fmt.Sprintf(" %s%3d  %7d%-3s  %s", cur, n+1, m.BufEndNum(), more, m.FileName))
+	}
+	src := NewSliceSource(lines)
+	src.SetEOF(true)
+	doc.SetSource(src)
+	return doc, nil
+}
@@ -0,0 +1,90 @@
+package oviewer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoot_closeDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		docs        int
+		current     int
+		n           int
+		wantErr     error
+		wantDocs    int
+		wantCurrent int
+	}{
+		{
+			name:        "testCloseCurrent",
+			docs:        3,
+			current:     1,
+			n:           1,
+			wantDocs:    2,
+			wantCurrent: 1,
+		},
+		{
+			name:        "testCloseLastCurrent",
+			docs:        3,
+			current:     2,
+			n:           2,
+			wantDocs:    2,
+			wantCurrent: 1,
+		},
+		{
+			name:        "testCloseBefore",
+			docs:        3,
+			current:     2,
+			n:           0,
+			wantDocs:    2,
+			wantCurrent: 1,
+		},
+		{
+			name:        "testCloseAfter",
+			docs:        3,
+			current:     0,
+			n:           1,
+			wantDocs:    2,
+			wantCurrent: 0,
+		},
+		{
+			name:        "testCloseOnlyDocument",
+			docs:        1,
+			current:     0,
+			n:           0,
+			wantErr:     ErrLastDocument,
+			wantDocs:    1,
+			wantCurrent: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := make([]*Document, tt.docs)
+			for i := range docs {
+				m, err := NewDocument()
+				if err != nil {
+					t.Fatal(err)
+				}
+				docs[i] = m
+			}
+			root, err := NewOviewer(docs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.CurrentDoc = tt.current
+			closed := docs[tt.n]
+			if err := root.closeDocument(tt.n); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Root.closeDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(root.DocList) != tt.wantDocs {
+				t.Errorf("len(Root.DocList) = %d, want %d", len(root.DocList), tt.wantDocs)
+			}
+			if root.CurrentDoc != tt.wantCurrent {
+				t.Errorf("Root.CurrentDoc = %d, want %d", root.CurrentDoc, tt.wantCurrent)
+			}
+			if tt.wantErr == nil && !closed.closed() {
+				t.Errorf("the document is not closed")
+			}
+		})
+	}
+}
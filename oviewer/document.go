@@ -1,9 +1,15 @@
 package oviewer
 
 import (
+	"fmt"
+	"hash/fnv"
 	"io"
+	"log"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"golang.org/x/crypto/ssh/terminal"
@@ -14,20 +20,37 @@ import (
 type Document struct {
 	// fileName is the file name to display.
 	FileName string
-	// lines stores the contents of the file in slices of strings.
-	// lines,endNum and eof is updated by reader goroutine.
-	lines []string
-	// endNum is the number of the last line read.
-	endNum int
-	// true if EOF is reached.
-	eof bool
-	// beforeSize represents the number of lines to read first.
-	beforeSize int
+	// src provides the lines of the document.
+	src LineSource
+	// filePath is the path of the file being read.
+	// It is empty if the document is not read from a file.
+	filePath string
+	// syntaxFile is the file name whose extension chooses the syntax in SyntaxAuto.
+	// A derived document has the file name of the document it is derived from.
+	syntaxFile string
 	// cache represents a cache of contents.
 	cache *ristretto.Cache
+	// lineMap maps each line to the line number of the source document.
+	// It is nil if the document is not derived from another document.
+	lineMap []int
+	// jsonPretty is true if the document is pretty-printed JSON.
+	// All lines are colorized in JSONColor mode.
+	jsonPretty bool
+	// lineOrder is the lines in the order of lineMap.
+	// It is nil if lineMap is in ascending order.
+	lineOrder []int
+	// derived is the documents that follow the lines of the document,
+	// and they are notified of the modified lines.
+	derived []*Document
+	// modified is the first line of the source document
+	// modified after it was derived, or -1.
+	modified int
 
 	// status is the display status of the document.
 	status
+	// follow is FollowMode that the reader goroutine reads atomically.
+	// It is set by setFollowMode and setStatus.
+	follow int32
 	// lineNum is the starting position of the current y.
 	lineNum int
 	// branch represents the number of wrapped lines.
@@ -36,6 +59,32 @@ type Document struct {
 	x int
 	// columnNum is the number of columns.
 	columnNum int
+	// marks maps the mark name to the line.
+	marks map[rune]int
+	// restoreLineNum is the position restored from the history.
+	// It moves there when the line is read.
+	restoreLineNum int
+
+	// csvStates is the states of the CSV parser at the start of the lines.
+	csvStates lineStates
+
+	// syntaxStates is the states of the lexer at the start of the lines.
+	syntaxStates lineStates
+
+	// diff is the index of the files and the hunks of a diff.
+	diff diffIndex
+	// diffMu controls the mutex of diff.
+	diffMu sync.Mutex
+
+	// section is the index of the section headings.
+	section sectionIndex
+	// sectionMu controls the mutex of section.
+	sectionMu sync.Mutex
+
+	// done is closed when the document is closed.
+	done chan struct{}
+	// closeOnce closes done only once.
+	closeOnce sync.Once
 
 	// mu controls the mutex.
 	mu sync.Mutex
@@ -44,10 +93,12 @@ type Document struct {
 // NewDocument returns Document.
 func NewDocument() (*Document, error) {
 	m := &Document{
-		lines:      make([]string, 0, 1000),
-		beforeSize: 1000,
+		src:      NewSliceSource(nil),
+		done:     make(chan struct{}),
+		modified: -1,
 		status: status{
 			ColumnDelimiter: "",
+			ColumnQuote:     DefaultColumnQuote,
 			TabWidth:        8,
 		},
 	}
@@ -58,54 +109,203 @@ func NewDocument() (*Document, error) {
 	return m, nil
 }
 
+// NewSourceDocument returns Document that displays the lines of src.
+func NewSourceDocument(src LineSource) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.SetSource(src)
+	return m, nil
+}
+
+// SetSource sets the source of the lines.
+func (m *Document) SetSource(src LineSource) {
+	m.src = src
+	src.SetNotify(m.changed)
+	m.resetCSV(-1)
+	m.resetSyntax(-1)
+	m.resetDiff(-1)
+	m.resetSection(-1)
+	m.ClearCache()
+}
+
 // ReadFile reads file.
+// Uncompressed regular files are read on demand by FileSource,
+// and the others are read into memory by ReaderSource.
 func (m *Document) ReadFile(fileName string) error {
-	var reader io.ReadCloser
 	if fileName == "" {
 		if terminal.IsTerminal(0) {
 			return ErrMissingFile
 		}
-		fileName = "(STDIN)"
-		reader = uncompressedReader(os.Stdin)
-	} else {
-		r, err := os.Open(fileName)
-		if err != nil {
+		_, reader := uncompressedReader(os.Stdin)
+		if err := m.ReadAll(reader); err != nil {
 			return err
 		}
-		reader = uncompressedReader(r)
+		m.FileName = "(STDIN)"
+		return nil
 	}
 
-	if err := m.ReadAll(reader); err != nil {
+	r, err := os.Open(fileName)
+	if err != nil {
 		return err
 	}
-
+	fi, err := r.Stat()
+	if err != nil {
+		r.Close()
+		return err
+	}
+	cFormat, ur := uncompressedReader(r)
+	m.filePath = fileName
 	m.FileName = fileName
 
+	if cFormat != uncompressed || !fi.Mode().IsRegular() {
+		return m.ReadAll(readCloser{Reader: ur, Closer: r})
+	}
+
+	r.Close()
+	src, err := NewFileSource(fileName, m.followMode)
+	if err != nil {
+		return err
+	}
+	m.SetSource(src)
+	m.waitReady()
 	return nil
 }
 
-// GetLine returns one line from buffer.
-func (m *Document) GetLine(lineNum int) string {
+// setStatus sets the display status of the document.
+func (m *Document) setStatus(s status) {
+	m.status = s
+	m.setFollowMode(s.FollowMode)
+}
+
+// inherit sets the status of src to the document derived from src.
+func (m *Document) inherit(src *Document) {
+	m.setStatus(src.status)
+	m.syntaxFile = src.syntaxFileName()
+}
+
+// syntaxFileName returns the file name that chooses the syntax.
+func (m *Document) syntaxFileName() string {
+	if m.syntaxFile != "" {
+		return m.syntaxFile
+	}
+	return m.FileName
+}
+
+// setFollowMode sets FollowMode.
+func (m *Document) setFollowMode(follow bool) {
+	m.FollowMode = follow
+	var v int32
+	if follow {
+		v = 1
+	}
+	atomic.StoreInt32(&m.follow, v)
+}
+
+// followMode returns FollowMode.
+// It is safe to call from the reader goroutine.
+func (m *Document) followMode() bool {
+	return atomic.LoadInt32(&m.follow) == 1
+}
+
+// waitReady waits until the source has read the first lines.
+func (m *Document) waitReady() {
+	rs, ok := m.src.(readySource)
+	if !ok {
+		return
+	}
+	select {
+	case <-rs.ready():
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+// followable returns true if the lines can be added after EOF.
+func (m *Document) followable() bool {
+	_, ok := m.src.(*FileSource)
+	return ok
+}
+
+// scanningStates returns true while the parser states or the indexes of the diff
+// and the sections are computed in the background.
+func (m *Document) scanningStates() bool {
+	return m.csvStates.busy() || m.syntaxStates.busy() || m.scanningDiff() || m.scanningSection()
+}
+
+// changed discards the cached contents of the modified lines.
+// It is called by the source.
+func (m *Document) changed(n int) {
+	m.resetCSV(n)
+	m.resetSyntax(n)
+	m.resetDiff(n)
+	m.resetSection(n)
+	// The cache is cleared even if only the last line is modified,
+	// because its keys include the settings of the panes.
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	if lineNum < 0 || lineNum >= len(m.lines) {
-		return ""
+	m.cache.Clear()
+	derived := append([]*Document(nil), m.derived...)
+	m.mu.Unlock()
+	for _, d := range derived {
+		d.sourceChanged(n)
+	}
+}
+
+// close closes the document.
+// It stops the reader goroutine and frees the cache.
+func (m *Document) close() {
+	m.closeOnce.Do(func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		close(m.done)
+		m.cache.Close()
+		m.cache = nil
+		if c, ok := m.src.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Println(err)
+			}
+		}
+	})
+}
+
+// closed returns true if the document is closed.
+func (m *Document) closed() bool {
+	select {
+	case <-m.done:
+		return true
+	default:
+		return false
 	}
-	return m.lines[lineNum]
+}
+
+// GetLine returns one line from buffer.
+func (m *Document) GetLine(lineNum int) string {
+	return m.src.Line(lineNum)
 }
 
 // BufEndNum return last line number.
 func (m *Document) BufEndNum() int {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.endNum
+	return m.src.Len()
+}
+
+// completeEndNum returns the number of the lines that are not modified by reading more.
+// The last line without a newline is excluded
+// unless the source is read to the end and not followed.
+func (m *Document) completeEndNum() int {
+	end := m.BufEndNum()
+	ps, ok := m.src.(partialSource)
+	if !ok || !ps.partialLine() {
+		return end
+	}
+	if m.BufEOF() && !(m.followable() && m.followMode()) {
+		return end
+	}
+	return end - 1
 }
 
 // BufEOF return true if EOF is reached.
 func (m *Document) BufEOF() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.eof
+	return m.src.EOF()
 }
 
 // NewCache creates a new cache.
@@ -133,7 +333,8 @@ func (m *Document) lineToContents(lineNum int, tabWidth int) (lineContents, erro
 		return nil, ErrOutOfRange
 	}
 
-	value, found := m.cache.Get(lineNum)
+	key := m.cacheKey(lineNum, tabWidth)
+	value, found := m.cache.Get(key)
 	if found {
 		lc, ok := value.(lineContents)
 		if !ok {
@@ -142,8 +343,17 @@ func (m *Document) lineToContents(lineNum int, tabWidth int) (lineContents, erro
 		return lc, nil
 	}
 
-	lc := parseString(m.GetLine(lineNum), tabWidth)
+	lc := m.parseLine(lineNum, tabWidth)
 
-	m.cache.Set(lineNum, lc, 1)
+	m.cache.Set(key, lc, 1)
 	return lc, nil
 }
+
+// cacheKey returns the key of the cached contents of the line.
+// The panes that display the same document can have different settings,
+// so the settings that change the contents are a part of the key.
+func (m *Document) cacheKey(lineNum int, tabWidth int) uint64 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d\x00%s\x00%s\x00%s\x00%s", tabWidth, m.JSONMode, strings.Join(m.JSONFields, ","), m.ColumnDelimiter, m.Syntax)
+	return uint64(h.Sum32())<<32 | uint64(uint32(lineNum))
+}
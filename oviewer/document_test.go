@@ -40,3 +40,25 @@ func TestDocument_ReadFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDocument_cacheKey(t *testing.T) {
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := m.cacheKey(1, 8)
+	if got := m.cacheKey(1, 8); got != key {
+		t.Errorf("Document.cacheKey() = %x, want %x", got, key)
+	}
+	if got := m.cacheKey(2, 8); got == key {
+		t.Errorf("Document.cacheKey() of the other line = %x, want different", got)
+	}
+	// The panes can display the document with the other settings.
+	if got := m.cacheKey(1, 4); got == key {
+		t.Errorf("Document.cacheKey() with TabWidth 4 = %x, want different", got)
+	}
+	m.JSONMode = JSONColor
+	if got := m.cacheKey(1, 8); got == key {
+		t.Errorf("Document.cacheKey() in JSON mode = %x, want different", got)
+	}
+}
@@ -13,16 +13,26 @@ func (root *Root) draw() {
 		return
 	}
 
+	if len(root.panes) > 1 {
+		root.drawPanes()
+	} else {
+		root.drawView()
+	}
+	root.tabBarDraw()
+	root.Show()
+}
+
+// drawView draws the document and the status line of the focused pane.
+func (root *Root) drawView() {
 	m := root.Doc
 	screen := root.Screen
 	if m.BufEndNum() == 0 || root.vHight == 0 {
 		root.Doc.lineNum = 0
 		root.statusDraw()
-		root.Show()
 		return
 	}
 
-	l, b := root.bottomLineNum(root.Doc.endNum)
+	l, b := root.bottomLineNum(root.Doc.BufEndNum())
 	if root.Doc.lineNum > l || (root.Doc.lineNum == l && root.Doc.branch > b) {
 		if root.Doc.BufEOF() {
 			root.message = "EOF"
@@ -35,6 +45,10 @@ func (root *Root) draw() {
 	}
 
 	root.lnumber = make([]lineNumber, root.vHight+1)
+	root.columnWidths = nil
+	if m.columnAligned() {
+		root.columnWidths = root.measureColumns()
+	}
 
 	lY := 0
 	lX := 0
@@ -49,14 +63,18 @@ func (root *Root) draw() {
 		if hy > root.vHight {
 			break
 		}
+		lc = lc.copy()
 		root.headerStyle(lc)
 
 		// column highlight
 		if root.input.mode == Normal && root.Doc.ColumnMode {
 			str, byteMap := contentsToStr(lc)
-			start, end := rangePosition(str, root.Doc.ColumnDelimiter, root.Doc.columnNum)
+			start, end := root.Doc.columnRange(lY, str, root.Doc.columnNum)
 			reverseContents(lc, byteMap[start], byteMap[end])
 		}
+		if root.Doc.columnAligned() {
+			lc = root.Doc.alignColumns(lY, lc, root.columnWidths)
+		}
 
 		root.lnumber[hy] = lineNumber{
 			line:   lY,
@@ -75,13 +93,20 @@ func (root *Root) draw() {
 				branch = 0
 			}
 		} else {
-			lX, lY = root.noWrapContents(hy, root.Doc.x, lY, lc)
+			lX, lY = root.noWrapContents(hy, root.Doc.x, lY, lc, root.frozenWidth(lY, lc))
 		}
 	}
 
+	// Section heading
+	bodyY := root.headerLen()
+	if n, ok := root.stickySection(); ok && bodyY < root.vHight {
+		root.drawSectionHeader(bodyY, n)
+		bodyY++
+	}
+
 	// Body
 	lX = root.Doc.branch * root.vWidth
-	for y := root.headerLen(); y < root.vHight; y++ {
+	for y := bodyY; y < root.vHight; y++ {
 		lc, err := m.lineToContents(root.Doc.lineNum+lY, root.Doc.TabWidth)
 		if err != nil {
 			// EOF
@@ -95,13 +120,13 @@ func (root *Root) draw() {
 			continue
 		}
 
-		for n := range lc {
-			lc[n].style = lc[n].style.Reverse(false)
-		}
-
-		if root.input.reg != nil || (root.input.mode == Normal && root.Doc.ColumnMode) {
+		if root.input.reg != nil || len(root.highlights) > 0 || (root.input.mode == Normal && root.Doc.ColumnMode) {
+			lc = lc.copy()
 			lineStr, byteMap := contentsToStr(lc)
 
+			// multiple highlights
+			root.highlightContents(lc, lineStr, byteMap)
+
 			// search highlight
 			if root.input.reg != nil {
 				poss := searchPosition(lineStr, root.input.reg)
@@ -112,18 +137,29 @@ func (root *Root) draw() {
 
 			// column highlight
 			if root.input.mode == Normal && root.Doc.ColumnMode {
-				start, end := rangePosition(lineStr, root.Doc.ColumnDelimiter, root.Doc.columnNum)
+				start, end := root.Doc.columnRange(root.Doc.lineNum+lY, lineStr, root.Doc.columnNum)
 				reverseContents(lc, byteMap[start], byteMap[end])
 			}
 		}
+		if root.Doc.columnAligned() {
+			lc = root.Doc.alignColumns(root.Doc.lineNum+lY, lc, root.columnWidths)
+		}
+
+		// mark
+		numX := 0
+		if root.Doc.hasMarks() {
+			root.drawMark(y, root.Doc.lineNum+lY)
+			numX = markWidth
+		}
 
 		// line number mode
 		if root.Doc.LineNumMode {
-			lineNum := strToContents(fmt.Sprintf("%*d", root.startX-1, root.Doc.lineNum+lY-root.Doc.Header+1), root.Doc.TabWidth)
+			num := root.Doc.lineNumber(root.Doc.lineNum+lY) - root.Doc.Header + 1
+			lineNum := strToContents(fmt.Sprintf("%*d", root.startX-numX-1, num), root.Doc.TabWidth)
 			for i := 0; i < len(lineNum); i++ {
 				lineNum[i].style = tcell.StyleDefault.Bold(true)
 			}
-			root.setContentString(0, y, lineNum)
+			root.setContentString(numX, y, lineNum)
 		}
 
 		root.lnumber[y] = lineNumber{
@@ -140,7 +176,7 @@ func (root *Root) draw() {
 				branch = 0
 			}
 		} else {
-			lX, nextY = root.noWrapContents(y, root.Doc.x, lY, lc)
+			lX, nextY = root.noWrapContents(y, root.Doc.x, lY, lc, root.frozenWidth(root.Doc.lineNum+lY, lc))
 		}
 
 		// alternate background color
@@ -159,12 +195,25 @@ func (root *Root) draw() {
 
 	root.bottomPos = root.Doc.lineNum + max(lY, 0) - 1
 
+	if root.input.mode == DocList || root.input.mode == DiffList {
+		root.drawListCursor()
+	}
+
 	if root.mouseSelect {
 		root.drawSelect(root.x1, root.y1, root.x2, root.y2, true)
 	}
 
 	root.statusDraw()
-	root.Show()
+}
+
+// drawMark draws the name of the mark on the line in the gutter.
+func (root *Root) drawMark(y int, lineNum int) {
+	name, ok := root.Doc.markOf(lineNum)
+	if !ok {
+		root.setContentString(0, y, lineContents{content{width: 1, style: tcell.StyleDefault.Normal()}})
+		return
+	}
+	root.setContentString(0, y, lineContents{content{mainc: name, width: 1, style: MarkStyle}})
 }
 
 // drawEOL fills with blanks from the end of the line to the screen width.
@@ -210,27 +259,54 @@ func (root *Root) wrapContents(y int, lX int, lY int, lc lineContents) (int, int
 }
 
 // noWrapContents draws contents without wrapping and returns the next drawing position.
-func (root *Root) noWrapContents(y int, lX int, lY int, lc lineContents) (int, int) {
+// The first frozen cells are drawn without scrolling.
+func (root *Root) noWrapContents(y int, lX int, lY int, lc lineContents, frozen int) (int, int) {
 	if lX < root.minStartX {
 		lX = root.minStartX
 	}
 	for x := 0; x+root.startX < root.vWidth; x++ {
-		if lX+x < 0 {
+		n := lX + x
+		if lX > 0 && x < frozen {
+			n = x
+		}
+		if n < 0 {
 			root.Screen.SetContent(x, y, 0, nil, tcell.StyleDefault.Normal())
 			continue
 		}
-		if lX+x >= len(lc) {
+		if n >= len(lc) {
 			// EOL
 			root.drawEOL(root.startX+x, y)
 			break
 		}
-		content := lc[lX+x]
+		content := lc[n]
 		root.Screen.SetContent(root.startX+x, y, content.mainc, content.combc, content.style)
 	}
 	lY++
 	return lX, lY
 }
 
+// drawSectionHeader draws the section heading of the line at y without wrapping.
+func (root *Root) drawSectionHeader(y int, lineNum int) {
+	m := root.Doc
+	lc, err := m.lineToContents(lineNum, m.TabWidth)
+	if err != nil {
+		return
+	}
+	lc = lc.copy()
+	root.headerStyle(lc)
+	if m.columnAligned() {
+		lc = m.alignColumns(lineNum, lc, root.columnWidths)
+	}
+	root.lnumber[y] = lineNumber{
+		line:   lineNum,
+		branch: 0,
+	}
+	for x := 0; x < root.startX; x++ {
+		root.Screen.SetContent(x, y, 0, nil, tcell.StyleDefault.Normal())
+	}
+	root.noWrapContents(y, m.x, lineNum, lc, root.frozenWidth(lineNum, lc))
+}
+
 // headerStyle applies the style of the header.
 func (root *Root) headerStyle(lc lineContents) {
 	for i := 0; i < len(lc); i++ {
@@ -239,6 +315,7 @@ func (root *Root) headerStyle(lc lineContents) {
 }
 
 // statusDraw draws a status line.
+// The left and right sides are drawn with the templates of Config.
 func (root *Root) statusDraw() {
 	screen := root.Screen
 	style := tcell.StyleDefault
@@ -246,19 +323,31 @@ func (root *Root) statusDraw() {
 	for x := 0; x < root.vWidth; x++ {
 		screen.SetContent(x, root.statusPos, 0, nil, style)
 	}
-	leftStatus := fmt.Sprintf("%s:%s", root.Doc.FileName, root.message)
+	values := root.statusValues()
+	leftFormat := root.StatusLeft
+	if leftFormat == "" {
+		leftFormat = DefaultStatusLeft
+	}
+	leftStatus := expandStatus(leftFormat, values)
 	leftContents := strToContents(leftStatus, -1)
 
 	input := root.input
 	caseSensitive := ""
-	if root.CaseSensitive && (input.mode == Search || input.mode == Backsearch) {
+	if root.CaseSensitive && (input.mode == Search || input.mode == Backsearch || input.mode == Filter) {
 		caseSensitive = "(Aa)"
 	}
+	if root.StripEscapeSequence && input.mode == Save {
+		caseSensitive = "(plain)"
+	}
 
 	switch input.mode {
-	case Normal, Help, LogDoc:
+	case Normal, Help, LogDoc, MarkList, DocList, DiffList:
+		leftStyle := root.StatusLeftStyle.style()
+		if root.inactivePane {
+			leftStyle = root.StatusRightStyle.style()
+		}
 		for i := 0; i < len(leftContents); i++ {
-			leftContents[i].style = leftContents[i].style.Reverse(true)
+			leftContents[i].style = leftStyle
 		}
 		root.Screen.ShowCursor(len(leftContents), root.statusPos)
 	default:
@@ -269,13 +358,16 @@ func (root *Root) statusDraw() {
 	}
 	root.setContentString(0, root.statusPos, leftContents)
 
-	next := ""
-	if !root.Doc.BufEOF() {
-		next = "..."
+	rightFormat := root.StatusRight
+	if rightFormat == "" {
+		rightFormat = DefaultStatusRight
+	}
+	rightContents := strToContents(expandStatus(rightFormat, values), -1)
+	rightStyle := root.StatusRightStyle.style()
+	for i := 0; i < len(rightContents); i++ {
+		rightContents[i].style = rightStyle
 	}
-	rightStatus := fmt.Sprintf("(%d/%d%s)", root.Doc.lineNum, root.Doc.BufEndNum(), next)
-	rightContents := strToContents(rightStatus, -1)
-	root.setContentString(root.vWidth-len(rightStatus), root.statusPos, rightContents)
+	root.setContentString(root.vWidth-len(rightContents), root.statusPos, rightContents)
 }
 
 // setContentString is a helper function that draws a string with setContent.
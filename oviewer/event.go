@@ -21,7 +21,7 @@ func (root *Root) main(quitChan chan<- struct{}) {
 		ev := root.Screen.PollEvent()
 		switch ev := ev.(type) {
 		case *eventAppQuit:
-			if root.input.mode == Help || root.input.mode == LogDoc {
+			if root.input.mode == Help || root.input.mode == LogDoc || root.input.mode == MarkList || root.input.mode == DocList || root.input.mode == DiffList {
 				root.toNormal()
 				continue
 			}
@@ -36,19 +36,41 @@ func (root *Root) main(quitChan chan<- struct{}) {
 		case *eventPaste:
 			root.getClipboard(ctx)
 		case *eventSearch:
-			root.search(ctx, root.Doc.lineNum+1, root.searchLine)
+			root.search(ctx, root.Doc.lineNum+1, true)
 		case *eventBackSearch:
-			root.search(ctx, root.Doc.lineNum-1, root.backSearchLine)
+			root.search(ctx, root.Doc.lineNum-1, false)
 		case *searchInput:
 			root.forwardSearch(ctx, ev.value)
 		case *backSearchInput:
 			root.backSearch(ctx, ev.value)
+		case *filterInput:
+			root.filter(ev.value)
+		case *highlightInput:
+			root.toggleHighlight(ev.value)
+		case *markInput:
+			root.setMark(ev.value)
+		case *jumpMarkInput:
+			root.jumpMark(ev.value)
+		case *pipeInput:
+			root.pipe(ev)
+		case *openInput:
+			root.openDocument(ev.value)
+		case *saveInput:
+			root.saveDocument(ev.value)
 		case *gotoInput:
 			root.goLine(ev.value)
 		case *headerInput:
 			root.setHeader(ev.value)
 		case *delimiterInput:
 			root.setDelimiter(ev.value)
+		case *frozenInput:
+			root.setFrozen(ev.value)
+		case *sortInput:
+			root.sortColumn(ctx, ev.value)
+		case *jsonInput:
+			root.setJSONMode(ev.value)
+		case *sectionDelimiterInput:
+			root.setSectionDelimiter(ev.value)
 		case *tabWidthInput:
 			root.setTabWidth(ev.value)
 		case *tcell.EventResize:
@@ -58,8 +80,16 @@ func (root *Root) main(quitChan chan<- struct{}) {
 		case *tcell.EventKey:
 			root.setMessage("")
 			switch root.input.mode {
-			case Normal, Help, LogDoc:
+			case Normal, Help, LogDoc, MarkList:
 				root.keyCapture(ev)
+			case DocList:
+				if !root.docListKey(ev) {
+					root.keyCapture(ev)
+				}
+			case DiffList:
+				if !root.diffListKey(ev) {
+					root.keyCapture(ev)
+				}
 			default:
 				root.inputEvent(ev)
 			}
@@ -92,7 +122,14 @@ func (root *Root) Quit() {
 }
 
 // Cancel usually does nothing.
+// In exec mode, it sends an interrupt signal to the command.
 func (root *Root) Cancel() {
+	if root.process == nil {
+		return
+	}
+	if err := root.process.interrupt(); err != nil {
+		root.setMessage(err.Error())
+	}
 }
 
 // WriteQuit sets the write flag and executes a quit event.
@@ -119,17 +156,25 @@ func (root *Root) runOnTime() {
 }
 
 // countTimer fires events periodically until it reaches EOF.
+// In follow mode, it continues to fire events after EOF.
+// It also fires events until the parser states are computed in the background.
 func (root *Root) countTimer() {
 	timer := time.NewTicker(time.Millisecond * 500)
-loop:
+	defer timer.Stop()
+	var doc *Document
+	eof := false
+	scanning := false
 	for {
 		<-timer.C
-		root.runOnTime()
-		if root.Doc.BufEOF() {
-			break loop
+		s := root.Doc.scanningStates()
+		if root.Doc == doc && root.Doc.BufEOF() && eof && !root.Doc.FollowMode && !scanning && !s {
+			continue
 		}
+		doc = root.Doc
+		eof = doc.BufEOF()
+		scanning = s
+		root.runOnTime()
 	}
-	timer.Stop()
 }
 
 // MoveLine fires an event that moves to the specified line.
@@ -155,7 +200,7 @@ func (root *Root) MoveTop() {
 
 // MoveBottom fires the event of moving to bottom.
 func (root *Root) MoveBottom() {
-	root.MoveLine(root.Doc.endNum)
+	root.MoveLine(root.Doc.BufEndNum())
 }
 
 // eventSearch represents search event.
@@ -0,0 +1,104 @@
+package oviewer
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// execProcess represents the process of the command executed in exec mode.
+type execProcess struct {
+	process *os.Process
+	// exited is true if the process has exited.
+	exited bool
+	// exitStatus is the exit status of the process.
+	exitStatus string
+
+	mu sync.Mutex
+}
+
+// ExecCommand executes the command and returns the structure of oviewer.
+// The stdout and stderr of the command are opened as separate documents.
+func ExecCommand(command *exec.Cmd) (*Root, error) {
+	docOut, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	docOut.FileName = "STDOUT"
+	docErr, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	docErr.FileName = "STDERR"
+
+	if !terminal.IsTerminal(0) {
+		command.Stdin = os.Stdin
+	}
+	outReader, outWriter := io.Pipe()
+	errReader, errWriter := io.Pipe()
+	command.Stdout = outWriter
+	command.Stderr = errWriter
+	setProcessGroup(command)
+
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &execProcess{process: command.Process}
+	go func() {
+		err := command.Wait()
+		if err != nil {
+			log.Printf("%s: %s", command.Path, err)
+		}
+		p.mu.Lock()
+		p.exited = true
+		state := command.ProcessState
+		if state.ExitCode() < 0 {
+			p.exitStatus = state.String()
+		} else {
+			p.exitStatus = fmt.Sprintf("exit:%d", state.ExitCode())
+		}
+		p.mu.Unlock()
+		// Close the pipes after the exit status is set,
+		// so that the redraw at EOF shows it.
+		outWriter.Close()
+		errWriter.Close()
+	}()
+
+	if err := docOut.ReadAll(outReader); err != nil {
+		return nil, err
+	}
+	if err := docErr.ReadAll(errReader); err != nil {
+		return nil, err
+	}
+
+	root, err := NewOviewer(docOut, docErr)
+	if err != nil {
+		return nil, err
+	}
+	root.process = p
+	return root, nil
+}
+
+// interrupt sends an interrupt signal to the process.
+func (p *execProcess) interrupt() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exited {
+		return nil
+	}
+	return interruptProcess(p.process)
+}
+
+// status returns the exit status of the process as a string.
+// It returns an empty string if the process is still running.
+func (p *execProcess) status() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exitStatus
+}
@@ -0,0 +1,63 @@
+package oviewer
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExecCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		wantOut    []string
+		wantErr    []string
+		wantStatus string
+	}{
+		{
+			name:       "testStdout",
+			command:    "echo foo; echo bar",
+			wantOut:    []string{"foo", "bar"},
+			wantErr:    []string{},
+			wantStatus: "exit:0",
+		},
+		{
+			name:       "testStderr",
+			command:    "echo foo; echo error >&2; exit 3",
+			wantOut:    []string{"foo"},
+			wantErr:    []string{"error"},
+			wantStatus: "exit:3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ExecCommand(exec.Command("sh", "-c", tt.command))
+			if err != nil {
+				t.Fatal(err)
+			}
+			docOut, docErr := root.DocList[0], root.DocList[1]
+			for i := 0; i < 100 && !(docOut.BufEOF() && docErr.BufEOF()); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			// The exit status is set before EOF is reached.
+			if got := root.process.status(); got != tt.wantStatus {
+				t.Errorf("execProcess.status() = %q, want %q", got, tt.wantStatus)
+			}
+			if got := docLines(docOut); !reflect.DeepEqual(got, tt.wantOut) {
+				t.Errorf("STDOUT = %v, want %v", got, tt.wantOut)
+			}
+			if got := docLines(docErr); !reflect.DeepEqual(got, tt.wantErr) {
+				t.Errorf("STDERR = %v, want %v", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func docLines(m *Document) []string {
+	lines := make([]string, m.BufEndNum())
+	for n := range lines {
+		lines[n] = m.GetLine(n)
+	}
+	return lines
+}
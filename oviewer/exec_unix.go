@@ -0,0 +1,29 @@
+// +build !windows
+
+package oviewer
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs the command in a new process group
+// so that the interrupt can be sent to the whole group.
+func setProcessGroup(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// shellCommand returns the command that runs the string in the shell.
+func shellCommand(command string) *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return exec.Command(shell, "-c", command)
+}
+
+// interruptProcess sends an interrupt signal to the process group.
+func interruptProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGINT)
+}
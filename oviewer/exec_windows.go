@@ -0,0 +1,21 @@
+package oviewer
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing on Windows.
+func setProcessGroup(command *exec.Cmd) {
+}
+
+// shellCommand returns the command that runs the string in cmd.exe.
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// interruptProcess kills the process,
+// because Windows cannot send an interrupt signal.
+func interruptProcess(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,209 @@
+package oviewer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// filter creates a document containing only the lines that match
+// the input and switches to it.
+// If the input starts with "!", the lines that do not match are shown.
+func (root *Root) filter(input string) {
+	if input == "" {
+		return
+	}
+	pattern := input
+	invert := false
+	if strings.HasPrefix(pattern, "!") {
+		invert = true
+		pattern = pattern[1:]
+	}
+	reg := regexpComple(pattern, root.CaseSensitive)
+	if reg == nil {
+		root.setMessage(ErrNotFound.Error())
+		return
+	}
+
+	src := root.DocList[root.CurrentDoc]
+	m, err := NewFilterDocument(src, reg, invert)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m.FileName = fmt.Sprintf("%s [&%s]", src.FileName, input)
+	root.insertDocument(m)
+	root.setMessage(fmt.Sprintf("filter:%s", input))
+}
+
+// NewFilterDocument returns a document that contains the lines of src
+// that match reg (or do not match if invert is true).
+// The header lines of src are always included.
+// The document keeps updating while src is being read.
+func NewFilterDocument(src *Document, reg *regexp.Regexp, invert bool) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.inherit(src)
+	m.lineMap = make([]int, 0)
+	lines := NewSliceSource(nil)
+	m.SetSource(lines)
+	// The header is copied because it can be changed while deriving.
+	header := src.Header
+	go m.deriveLines(src, lines, func(n int, line string) {
+		if n < header || matchLine(reg, line) != invert {
+			m.appendMapped(lines, line, src.lineNumber(n))
+		}
+	})
+	return m, nil
+}
+
+// deriveLines calls fn for each line of src to append the lines to lines.
+// It follows src while src is being read.
+// The last line of src is not derived until it ends with a newline,
+// and all lines are derived again if the derived lines of src are modified,
+// for example when a followed file is truncated or rotated.
+// lines reaches EOF when it returns, even if src or the document is closed.
+func (m *Document) deriveLines(src *Document, lines *SliceSource, fn func(n int, line string)) {
+	defer lines.SetEOF(true)
+	src.addDerived(m)
+	defer src.removeDerived(m)
+	n := 0
+	for {
+		if modified := m.takeModified(); modified >= 0 && modified < n {
+			m.mu.Lock()
+			m.lineMap = m.lineMap[:0]
+			m.mu.Unlock()
+			lines.Reset()
+			n = 0
+		}
+		end := src.completeEndNum()
+		for ; n < end; n++ {
+			fn(n, src.GetLine(n))
+			if m.closed() {
+				return
+			}
+		}
+
+		eof := src.BufEOF() && n >= src.completeEndNum()
+		lines.SetEOF(eof)
+		if eof && !src.followable() {
+			return
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-src.done:
+			return
+		case <-time.After(followInterval):
+		}
+	}
+}
+
+// addDerived adds the document d that is notified of the modified lines.
+func (m *Document) addDerived(d *Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.derived = append(m.derived, d)
+}
+
+// removeDerived removes the document d added by addDerived.
+func (m *Document) removeDerived(d *Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, v := range m.derived {
+		if v == d {
+			m.derived = append(m.derived[:i], m.derived[i+1:]...)
+			return
+		}
+	}
+}
+
+// sourceChanged records the first modified line of the source document.
+func (m *Document) sourceChanged(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.modified < 0 || n < m.modified {
+		m.modified = n
+	}
+}
+
+// takeModified returns the first modified line of the source document
+// recorded by sourceChanged, or -1, and clears it.
+func (m *Document) takeModified() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.modified
+	m.modified = -1
+	return n
+}
+
+// appendMapped appends a line with the line number of the source document.
+// The line number is appended first so that it exists for the visible lines.
+// num is the line number of the original document, which is mapped
+// by lineNumber of src if src is also derived.
+func (m *Document) appendMapped(lines *SliceSource, line string, num int) {
+	m.mu.Lock()
+	m.lineMap = append(m.lineMap, num)
+	m.mu.Unlock()
+	lines.Append(line)
+}
+
+// lineNumber returns the line number of the source document.
+// It returns lineNum as it is if the document is not derived.
+func (m *Document) lineNumber(lineNum int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lineMap == nil || lineNum < 0 || lineNum >= len(m.lineMap) {
+		return lineNum
+	}
+	return m.lineMap[lineNum]
+}
+
+// lastLineNumber returns the largest line number of the source document.
+func (m *Document) lastLineNumber() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lineOrder != nil {
+		return m.lineMap[m.lineOrder[len(m.lineOrder)-1]]
+	}
+	lineNum := m.src.Len() - 1
+	if m.lineMap == nil || lineNum < 0 || lineNum >= len(m.lineMap) {
+		return lineNum
+	}
+	return m.lineMap[lineNum]
+}
+
+// lineIndex returns the line number of the document
+// from the line number of the source document.
+// If there is no such line, the next line is returned.
+func (m *Document) lineIndex(num int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lineMap == nil {
+		return num
+	}
+	if m.lineOrder != nil {
+		i := sort.Search(len(m.lineOrder), func(i int) bool {
+			return m.lineMap[m.lineOrder[i]] >= num
+		})
+		if i >= len(m.lineOrder) {
+			return len(m.lineMap)
+		}
+		return m.lineOrder[i]
+	}
+	return sort.SearchInts(m.lineMap, num)
+}
+
+// matchLine returns true if the line matches the regular expression.
+// Escape sequences are excluded before matching.
+func matchLine(reg *regexp.Regexp, line string) bool {
+	if strings.ContainsAny(line, "\x1b\b") {
+		line = stripEscapeSequence.ReplaceAllString(line, "")
+	}
+	return reg.MatchString(line)
+}
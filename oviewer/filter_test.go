@@ -0,0 +1,231 @@
+package oviewer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewFilterDocument(t *testing.T) {
+	type args struct {
+		str    string
+		header int
+		reg    *regexp.Regexp
+		invert bool
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantLines []string
+		wantMap   []int
+	}{
+		{
+			name: "testFilter",
+			args: args{
+				str: "foo\nbar\nbaz\n",
+				reg: regexp.MustCompile(`ba`),
+			},
+			wantLines: []string{"bar", "baz"},
+			wantMap:   []int{1, 2},
+		},
+		{
+			name: "testInvert",
+			args: args{
+				str:    "foo\nbar\nbaz\n",
+				reg:    regexp.MustCompile(`ba`),
+				invert: true,
+			},
+			wantLines: []string{"foo"},
+			wantMap:   []int{0},
+		},
+		{
+			name: "testHeader",
+			args: args{
+				str:    "name\nfoo\nbar\n",
+				header: 1,
+				reg:    regexp.MustCompile(`bar`),
+			},
+			wantLines: []string{"name", "bar"},
+			wantMap:   []int{0, 2},
+		},
+		{
+			name: "testEscapeSequences",
+			args: args{
+				str: "\x1B[31mfoo\x1B[0m\nbar\n",
+				reg: regexp.MustCompile(`^foo$`),
+			},
+			wantLines: []string{"\x1B[31mfoo\x1B[0m"},
+			wantMap:   []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			src.Header = tt.args.header
+			if err := src.ReadAll(ioutil.NopCloser(bytes.NewBufferString(tt.args.str))); err != nil {
+				t.Fatal(err)
+			}
+			m, err := NewFilterDocument(src, tt.args.reg, tt.args.invert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 50 && !m.BufEOF(); i++ {
+				time.Sleep(followInterval)
+			}
+			lines := make([]string, 0)
+			lineMap := make([]int, 0)
+			for n := 0; n < m.BufEndNum(); n++ {
+				lines = append(lines, m.GetLine(n))
+				lineMap = append(lineMap, m.lineNumber(n))
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("NewFilterDocument() lines = %v, want %v", lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(lineMap, tt.wantMap) {
+				t.Errorf("NewFilterDocument() lineMap = %v, want %v", lineMap, tt.wantMap)
+			}
+		})
+	}
+}
+
+func TestNewFilterDocument_nested(t *testing.T) {
+	src, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.ReadAll(ioutil.NopCloser(bytes.NewBufferString("foo\nbar\nbaz\nbax\n"))); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := NewFilterDocument(src, regexp.MustCompile(`ba`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewFilterDocument(parent, regexp.MustCompile(`x`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50 && !m.BufEOF(); i++ {
+		time.Sleep(followInterval)
+	}
+	if got := m.BufEndNum(); got != 1 {
+		t.Fatalf("NewFilterDocument() lines = %d, want 1", got)
+	}
+	// The line number is that of the original document, not of the parent filter.
+	if got := m.lineNumber(0); got != 3 {
+		t.Errorf("NewFilterDocument() lineNumber(0) = %d, want 3", got)
+	}
+	if got := m.lineIndex(3); got != 0 {
+		t.Errorf("NewFilterDocument() lineIndex(3) = %d, want 0", got)
+	}
+}
+
+func TestNewFilterDocument_closed(t *testing.T) {
+	// src is not EOF because it is still being read.
+	src, err := NewSourceDocument(NewSliceSource([]string{"foo", "bar"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewFilterDocument(src, regexp.MustCompile(`ba`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.close()
+	for i := 0; i < 50 && !m.BufEOF(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !m.BufEOF() {
+		t.Error("NewFilterDocument() EOF = false after the source is closed, want true")
+	}
+}
+
+func TestNewFilterDocument_header(t *testing.T) {
+	s := NewSliceSource([]string{"foo", "bar", "baz"})
+	src, err := NewSourceDocument(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Header = 1
+	m, err := NewFilterDocument(src, regexp.MustCompile(`baz`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The header changed after the filter does not change the filtered lines.
+	src.Header = 2
+	s.SetEOF(true)
+	for i := 0; i < 50 && !m.BufEOF(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	lines := make([]string, 0)
+	for n := 0; n < m.BufEndNum(); n++ {
+		lines = append(lines, m.GetLine(n))
+	}
+	if want := []string{"foo", "baz"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("NewFilterDocument() lines = %v, want %v", lines, want)
+	}
+}
+
+func TestNewFilterDocument_follow(t *testing.T) {
+	f, err := ioutil.TempFile("", "ov-filter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.WriteString("info: start\nerror: par"); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.close()
+	src.setFollowMode(true)
+	if err := src.ReadFile(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewFilterDocument(src, regexp.MustCompile(`error`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.close()
+	filtered := func(want []string) {
+		t.Helper()
+		var lines []string
+		for i := 0; i < 50; i++ {
+			lines = make([]string, 0)
+			for n := 0; n < m.BufEndNum(); n++ {
+				lines = append(lines, m.GetLine(n))
+			}
+			if reflect.DeepEqual(lines, want) {
+				return
+			}
+			time.Sleep(followInterval)
+		}
+		t.Errorf("NewFilterDocument() lines = %v, want %v", lines, want)
+	}
+
+	// The unfinished last line is not filtered.
+	time.Sleep(followInterval * 2)
+	filtered([]string{})
+	if _, err := f.WriteString("tial line\n"); err != nil {
+		t.Fatal(err)
+	}
+	filtered([]string{"error: partial line"})
+
+	// The lines are filtered again from the beginning of the truncated file.
+	if err := f.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("error: new\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+	filtered([]string{"error: new"})
+}
@@ -0,0 +1,79 @@
+package oviewer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseFrozen parses the input of the frozen columns.
+// "N" is the number of columns, and "Nw" is the width in cells.
+func parseFrozen(input string) (columns int, width int, err error) {
+	input = strings.TrimSpace(input)
+	str := strings.TrimSuffix(input, "w")
+	n, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, 0, ErrInvalidNumber
+	}
+	if n < 0 {
+		return 0, 0, ErrOutOfRange
+	}
+	if str != input {
+		return 0, n, nil
+	}
+	return n, 0, nil
+}
+
+// setFrozen sets the columns that are not scrolled horizontally.
+func (root *Root) setFrozen(input string) {
+	columns, width, err := parseFrozen(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	if width >= root.vWidth-root.startX {
+		root.setMessage(ErrOutOfRange.Error())
+		return
+	}
+	root.Doc.FrozenColumns = columns
+	root.Doc.FrozenWidth = width
+	if root.Doc.ColumnMode {
+		root.Doc.columnNum = max(root.Doc.columnNum, columns)
+		root.Doc.x = root.columnModeX()
+	}
+	if width > 0 {
+		root.setMessage(fmt.Sprintf("Set frozen width %d", width))
+		return
+	}
+	root.setMessage(fmt.Sprintf("Set frozen columns %d", columns))
+}
+
+// frozenWidth returns the width of the frozen prefix of the contents of the line.
+// The frozen columns include the delimiter after them.
+func (root *Root) frozenWidth(lineNum int, lc lineContents) int {
+	m := root.Doc
+	if m.FrozenColumns <= 0 {
+		return min(m.FrozenWidth, len(lc))
+	}
+	str, byteMap := contentsToStr(lc)
+	ranges, first := m.columnRanges(lineNum, str)
+	n := m.FrozenColumns - first
+	if n <= 0 {
+		return 0
+	}
+	if n >= len(ranges) {
+		return len(lc)
+	}
+	return byteMap[ranges[n][0]]
+}
+
+// contentX returns the position in the contents of the line at x of the screen.
+func (root *Root) contentX(lineNum int, lc lineContents, x int) int {
+	if root.Doc.WrapMode || root.Doc.x <= 0 {
+		return root.Doc.x + x
+	}
+	if x-root.startX < root.frozenWidth(lineNum, lc) {
+		return x
+	}
+	return root.Doc.x + x
+}
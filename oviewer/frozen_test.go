@@ -0,0 +1,66 @@
+package oviewer
+
+import "testing"
+
+func Test_parseFrozen(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantColumns int
+		wantWidth   int
+		wantErr     error
+	}{
+		{name: "columns", input: "2", wantColumns: 2},
+		{name: "width", input: "20w", wantWidth: 20},
+		{name: "zero", input: "0", wantColumns: 0},
+		{name: "space", input: " 3 ", wantColumns: 3},
+		{name: "negative", input: "-1", wantErr: ErrOutOfRange},
+		{name: "invalid", input: "abc", wantErr: ErrInvalidNumber},
+		{name: "empty", input: "", wantErr: ErrInvalidNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, width, err := parseFrozen(tt.input)
+			if err != tt.wantErr {
+				t.Errorf("parseFrozen() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if columns != tt.wantColumns || width != tt.wantWidth {
+				t.Errorf("parseFrozen() = %v, %v, want %v, %v", columns, width, tt.wantColumns, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestRoot_frozenWidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns int
+		width   int
+		line    string
+		want    int
+	}{
+		{name: "none", line: "a,bb,c", want: 0},
+		{name: "oneColumn", columns: 1, line: "a,bb,c", want: 2},
+		{name: "twoColumns", columns: 2, line: "a,bb,c", want: 5},
+		{name: "allColumns", columns: 5, line: "a,bb,c", want: 6},
+		{name: "width", width: 4, line: "a,bb,c", want: 4},
+		{name: "widthShortLine", width: 10, line: "a,bb,c", want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &Root{}
+			m, err := NewSourceDocument(NewSliceSource([]string{tt.line}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.ColumnDelimiter = ","
+			m.FrozenColumns = tt.columns
+			m.FrozenWidth = tt.width
+			root.Doc = m
+			if got := root.frozenWidth(0, strToContents(tt.line, 8)); got != tt.want {
+				t.Errorf("Root.frozenWidth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package oviewer
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gdamore/tcell"
+)
+
+// highlight represents a pattern that is highlighted in its own color.
+type highlight struct {
+	word  string
+	reg   *regexp.Regexp
+	color tcell.Color
+}
+
+// DefaultColorHighlight is the default palette of highlight colors.
+var DefaultColorHighlight = []string{
+	"yellow",
+	"aqua",
+	"fuchsia",
+	"lime",
+	"orange",
+	"silver",
+}
+
+// setHighlights sets the highlights from the config.
+func (root *Root) setHighlights() {
+	root.highlights = nil
+	for _, word := range root.Config.Highlights {
+		root.addHighlight(word)
+	}
+}
+
+// toggleHighlight adds the pattern to the highlights,
+// or removes it if it is already highlighted.
+func (root *Root) toggleHighlight(word string) {
+	if word == "" {
+		return
+	}
+	for n, h := range root.highlights {
+		if h.word == word {
+			root.highlights = append(root.highlights[:n], root.highlights[n+1:]...)
+			root.setMessage(fmt.Sprintf("Remove highlight %s", word))
+			return
+		}
+	}
+	if root.addHighlight(word) {
+		root.setMessage(fmt.Sprintf("Add highlight %s", word))
+	}
+}
+
+// addHighlight adds the pattern to the highlights with an unused color.
+func (root *Root) addHighlight(word string) bool {
+	reg := regexpComple(word, root.CaseSensitive)
+	if reg == nil {
+		return false
+	}
+	root.highlights = append(root.highlights, highlight{
+		word:  word,
+		reg:   reg,
+		color: root.highlightColor(),
+	})
+	return true
+}
+
+// highlightColor returns the first color in the palette
+// that is not used by the highlights.
+func (root *Root) highlightColor() tcell.Color {
+	palette := root.ColorHighlight
+	if len(palette) == 0 {
+		palette = DefaultColorHighlight
+	}
+	for _, name := range palette {
+		color := tcell.GetColor(name)
+		used := false
+		for _, h := range root.highlights {
+			if h.color == color {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return color
+		}
+	}
+	return tcell.GetColor(palette[len(root.highlights)%len(palette)])
+}
+
+// highlightWords returns a list of the highlighted patterns.
+func (root *Root) highlightWords() []string {
+	words := make([]string, 0, len(root.highlights))
+	for _, h := range root.highlights {
+		words = append(words, h.word)
+	}
+	return words
+}
+
+// highlightContents applies the highlight colors to the contents.
+func (root *Root) highlightContents(lc lineContents, lineStr string, byteMap map[int]int) {
+	for _, h := range root.highlights {
+		for _, r := range searchPosition(lineStr, h.reg) {
+			for n := byteMap[r[0]]; n < byteMap[r[1]]; n++ {
+				lc[n].style = lc[n].style.Background(h.color).Foreground(tcell.ColorBlack)
+			}
+		}
+	}
+}
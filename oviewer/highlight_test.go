@@ -0,0 +1,69 @@
+package oviewer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestRoot_toggleHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		palette []string
+		words   []string
+		want    []tcell.Color
+	}{
+		{
+			name:  "testDefault",
+			words: []string{"a", "b", "c"},
+			want:  []tcell.Color{tcell.ColorYellow, tcell.ColorAqua, tcell.ColorFuchsia},
+		},
+		{
+			name:  "testRemove",
+			words: []string{"a", "b", "c", "b", "d"},
+			want:  []tcell.Color{tcell.ColorYellow, tcell.ColorFuchsia, tcell.ColorAqua},
+		},
+		{
+			name:    "testPalette",
+			palette: []string{"red", "blue"},
+			words:   []string{"a", "b"},
+			want:    []tcell.Color{tcell.ColorRed, tcell.ColorBlue},
+		},
+		{
+			name:    "testWrap",
+			palette: []string{"red", "blue"},
+			words:   []string{"a", "b", "c"},
+			want:    []tcell.Color{tcell.ColorRed, tcell.ColorBlue, tcell.ColorRed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, err := NewOviewer(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			screen := tcell.NewSimulationScreen("")
+			if err := screen.Init(); err != nil {
+				t.Fatal(err)
+			}
+			defer screen.Fini()
+			root.Screen = screen
+			root.ColorHighlight = tt.palette
+			for _, word := range tt.words {
+				root.toggleHighlight(word)
+			}
+			got := make([]tcell.Color, 0, len(root.highlights))
+			for _, h := range root.highlights {
+				got = append(got, h.color)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Root.toggleHighlight() colors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
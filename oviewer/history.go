@@ -0,0 +1,292 @@
+package oviewer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultHistorySize is the number of entries saved in each history.
+const defaultHistorySize = 100
+
+// History is the input history and the file positions saved between sessions.
+type History struct {
+	Search    []string `json:"search"`
+	Goto      []string `json:"goto"`
+	Delimiter []string `json:"delimiter"`
+	TabWidth  []string `json:"tabwidth"`
+	Pipe      []string `json:"pipe"`
+	Open      []string `json:"open"`
+	// Files is the position of each file keyed by absolute path.
+	Files map[string]FilePosition `json:"files"`
+}
+
+// FilePosition is the position and marks of a file.
+type FilePosition struct {
+	LineNum int            `json:"line"`
+	Marks   map[string]int `json:"marks,omitempty"`
+	Time    time.Time      `json:"time"`
+}
+
+// DefaultHistoryFile returns the path of the history file.
+// It is placed in $XDG_STATE_HOME/ov (default is ~/.local/state/ov).
+func DefaultHistoryFile() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "ov", "history.json")
+}
+
+// historyFile returns the path of the history file to use.
+// It returns an empty string if the history is disabled.
+func (root *Root) historyFile() string {
+	if root.DisableHistory {
+		return ""
+	}
+	return root.HistoryFile
+}
+
+// historySize returns the number of entries to save.
+func (root *Root) historySize() int {
+	if root.HistorySize > 0 {
+		return root.HistorySize
+	}
+	return defaultHistorySize
+}
+
+// readHistory reads the history file.
+// A missing file returns an empty history.
+func readHistory(fileName string) (*History, error) {
+	history := &History{}
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// writeHistory writes the history file.
+func writeHistory(fileName string, history *History) error {
+	b, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return err
+	}
+	tmp := fileName + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fileName)
+}
+
+// loadHistory restores the input history and the positions of the documents.
+func (root *Root) loadHistory() {
+	fileName := root.historyFile()
+	if fileName == "" {
+		return
+	}
+	history, err := readHistory(fileName)
+	if err != nil {
+		log.Printf("history: %s", err)
+		return
+	}
+
+	input := root.input
+	input.SearchCandidate.list = mergeList(input.SearchCandidate.list, history.Search)
+	input.GoCandidate.list = mergeList(input.GoCandidate.list, history.Goto)
+	input.DelimiterCandidate.list = mergeList(input.DelimiterCandidate.list, history.Delimiter)
+	input.TabWidthCandidate.list = mergeList(input.TabWidthCandidate.list, history.TabWidth)
+	input.PipeCandidate.list = mergeList(input.PipeCandidate.list, history.Pipe)
+	input.OpenCandidate.list = mergeList(input.OpenCandidate.list, history.Open)
+
+	for _, m := range root.DocList {
+		history.restore(m)
+	}
+	root.restorePosition()
+}
+
+// restoreFile restores the position of the document opened later.
+func (root *Root) restoreFile(m *Document) {
+	fileName := root.historyFile()
+	if fileName == "" {
+		return
+	}
+	history, err := readHistory(fileName)
+	if err != nil {
+		log.Printf("history: %s", err)
+		return
+	}
+	history.restore(m)
+}
+
+// restore sets the marks and the position to restore of the document.
+func (history *History) restore(m *Document) {
+	if m.filePath == "" {
+		return
+	}
+	path, err := filepath.Abs(m.filePath)
+	if err != nil {
+		return
+	}
+	pos, ok := history.Files[path]
+	if !ok {
+		return
+	}
+	for name, lineNum := range pos.Marks {
+		if r, ok := markName(name); ok {
+			m.setMark(r, lineNum)
+		}
+	}
+	m.restoreLineNum = pos.LineNum
+}
+
+// saveHistory saves the input history and the positions of the documents.
+// The history file is read again so that the positions of
+// files saved by other processes are kept.
+func (root *Root) saveHistory() {
+	fileName := root.historyFile()
+	if fileName == "" {
+		return
+	}
+	history, err := readHistory(fileName)
+	if err != nil {
+		log.Printf("history: %s", err)
+		history = &History{}
+	}
+
+	size := root.historySize()
+	input := root.input
+	history.Search = lastList(input.SearchCandidate.list, size)
+	history.Goto = lastList(input.GoCandidate.list, size)
+	history.Delimiter = lastList(input.DelimiterCandidate.list, size)
+	history.TabWidth = lastList(input.TabWidthCandidate.list, size)
+	history.Pipe = lastList(input.PipeCandidate.list, size)
+	history.Open = lastList(input.OpenCandidate.list, size)
+
+	if history.Files == nil {
+		history.Files = make(map[string]FilePosition)
+	}
+	now := time.Now()
+	for _, m := range root.DocList {
+		history.setPosition(m, now)
+	}
+	limitFiles(history.Files, size)
+
+	if err := writeHistory(fileName, history); err != nil {
+		log.Printf("history: %s", err)
+	}
+}
+
+// saveFilePosition saves the position of the document that is closed.
+func (root *Root) saveFilePosition(m *Document) {
+	fileName := root.historyFile()
+	if fileName == "" || m.filePath == "" {
+		return
+	}
+	history, err := readHistory(fileName)
+	if err != nil {
+		log.Printf("history: %s", err)
+		return
+	}
+	if history.Files == nil {
+		history.Files = make(map[string]FilePosition)
+	}
+	history.setPosition(m, time.Now())
+	limitFiles(history.Files, root.historySize())
+
+	if err := writeHistory(fileName, history); err != nil {
+		log.Printf("history: %s", err)
+	}
+}
+
+// setPosition sets the position and the marks of the document read from a file.
+func (history *History) setPosition(m *Document, now time.Time) {
+	if m.filePath == "" {
+		return
+	}
+	path, err := filepath.Abs(m.filePath)
+	if err != nil {
+		return
+	}
+	pos := FilePosition{
+		LineNum: m.lineNum,
+		Time:    now,
+	}
+	for _, name := range m.markNames() {
+		if pos.Marks == nil {
+			pos.Marks = make(map[string]int)
+		}
+		pos.Marks[string(name)] = m.marks[name]
+	}
+	history.Files[path] = pos
+}
+
+// restorePosition moves the documents to the restored position
+// when the lines have been read.
+func (root *Root) restorePosition() {
+	for _, m := range root.DocList {
+		if m.restoreLineNum == 0 {
+			continue
+		}
+		// Cancel when it has already been moved.
+		if m.lineNum != 0 {
+			m.restoreLineNum = 0
+			continue
+		}
+		if m.BufEndNum() <= m.restoreLineNum+m.Header+root.vHight && !m.BufEOF() {
+			continue
+		}
+		m.lineNum = m.restoreLineNum
+		m.restoreLineNum = 0
+	}
+}
+
+// mergeList adds the history to the end of the list without duplicates.
+func mergeList(list []string, history []string) []string {
+	for _, s := range history {
+		list = toLast(list, s)
+	}
+	return list
+}
+
+// lastList returns the last size entries of the list.
+func lastList(list []string, size int) []string {
+	if len(list) > size {
+		return list[len(list)-size:]
+	}
+	return list
+}
+
+// limitFiles removes the oldest files so that the number of files is less than or equal to size.
+func limitFiles(files map[string]FilePosition, size int) {
+	if len(files) <= size {
+		return
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return files[paths[i]].Time.After(files[paths[j]].Time)
+	})
+	for _, path := range paths[size:] {
+		delete(files, path)
+	}
+}
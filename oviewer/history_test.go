@@ -0,0 +1,159 @@
+package oviewer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoot_saveHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(fileName, []byte("a\nb\nc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	historyFile := filepath.Join(dir, "state", "history.json")
+
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ReadFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewOviewer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.HistoryFile = historyFile
+	root.input.SearchCandidate.list = []string{"foo", "bar"}
+	m.lineNum = 1
+	m.setMark('a', 2)
+	root.saveHistory()
+
+	m2, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m2.ReadFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	root2, err := NewOviewer(m2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root2.HistoryFile = historyFile
+	root2.loadHistory()
+
+	if got, want := root2.input.SearchCandidate.list, []string{"foo", "bar"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchCandidate = %v, want %v", got, want)
+	}
+	if got, want := m2.marks, map[rune]int{'a': 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("marks = %v, want %v", got, want)
+	}
+	if m2.lineNum+m2.restoreLineNum != 1 {
+		t.Errorf("position = %v, want %v", m2.lineNum+m2.restoreLineNum, 1)
+	}
+}
+
+func TestRoot_historyFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		historyFile    string
+		disableHistory bool
+		want           string
+	}{
+		{name: "default", want: ""},
+		{name: "set", historyFile: "history.json", want: "history.json"},
+		{name: "disable", historyFile: "history.json", disableHistory: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := NewOviewer(&Document{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.HistoryFile = tt.historyFile
+			root.DisableHistory = tt.disableHistory
+			if got := root.historyFile(); got != tt.want {
+				t.Errorf("Root.historyFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitFiles(t *testing.T) {
+	now := time.Now()
+	files := map[string]FilePosition{
+		"/a": {Time: now.Add(-3 * time.Hour)},
+		"/b": {Time: now.Add(-1 * time.Hour)},
+		"/c": {Time: now.Add(-2 * time.Hour)},
+	}
+	limitFiles(files, 2)
+	if _, ok := files["/a"]; ok || len(files) != 2 {
+		t.Errorf("limitFiles() = %v, want the oldest removed", files)
+	}
+}
+
+func TestRoot_closeDocument_history(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(fileName, []byte("a\nb\nc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	historyFile := filepath.Join(dir, "state", "history.json")
+
+	docs := make([]*Document, 2)
+	for i := range docs {
+		m, err := NewDocument()
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs[i] = m
+	}
+	if err := docs[1].ReadFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewOviewer(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.HistoryFile = historyFile
+	docs[1].lineNum = 2
+	docs[1].setMark('a', 1)
+	if err := root.closeDocument(1); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := readHistory(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := filepath.Abs(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos, ok := history.Files[path]
+	if !ok {
+		t.Fatalf("the position of %s is not saved", path)
+	}
+	if pos.LineNum != 2 {
+		t.Errorf("LineNum = %d, want 2", pos.LineNum)
+	}
+	if want := map[string]int{"a": 1}; !reflect.DeepEqual(pos.Marks, want) {
+		t.Errorf("Marks = %v, want %v", pos.Marks, want)
+	}
+}
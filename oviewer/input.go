@@ -22,6 +22,10 @@ type Input struct {
 	GoCandidate        *candidate
 	DelimiterCandidate *candidate
 	TabWidthCandidate  *candidate
+	PipeCandidate      *candidate
+	OpenCandidate      *candidate
+	JSONCandidate      *candidate
+	SectionCandidate   *candidate
 }
 
 // InputMode represents the state of the input.
@@ -46,6 +50,34 @@ const (
 	Delimiter
 	// TabWidth is the tab number input mode.
 	TabWidth
+	// Filter is a filter input mode.
+	Filter
+	// Highlight is a highlight input mode.
+	Highlight
+	// Mark is the mark name input mode.
+	Mark
+	// JumpMark is the input mode of the mark name to jump to.
+	JumpMark
+	// MarkList is the mark list screen mode.
+	MarkList
+	// Save is the input mode of the file name to save.
+	Save
+	// Pipe is the input mode of the command to pipe.
+	Pipe
+	// DocList is the document list screen mode.
+	DocList
+	// OpenFile is the input mode of the file name to open.
+	OpenFile
+	// Frozen is the input mode of the frozen columns.
+	Frozen
+	// Sort is the input mode of the sort option.
+	Sort
+	// JSON is the input mode of the JSON mode.
+	JSON
+	// DiffList is the screen mode of the file list of the diff.
+	DiffList
+	// Section is the input mode of the section delimiter.
+	Section
 )
 
 // InputEvent input key events.
@@ -134,6 +166,11 @@ func (root *Root) inputKeyEvent(ev *tcell.EventKey) bool {
 		runes := []rune(input.value)
 		input.cursorX = runeWidth(string(runes))
 	case tcell.KeyTAB:
+		if c, ok := input.EventInput.(completer); ok {
+			input.value = c.complete(input.value)
+			input.cursorX = runeWidth(input.value)
+			return false
+		}
 		pos := stringWidth(input.value, input.cursorX+1)
 		runes := []rune(input.value)
 		input.value = string(runes[:pos])
@@ -141,8 +178,17 @@ func (root *Root) inputKeyEvent(ev *tcell.EventKey) bool {
 		input.cursorX += 2
 		input.value += string(runes[pos:])
 	case tcell.KeyCtrlA:
+		if input.mode == Save {
+			root.toggleStripEscapeSequence()
+			return false
+		}
 		root.CaseSensitive = !root.CaseSensitive
 	case tcell.KeyRune:
+		if input.mode == Mark || input.mode == JumpMark {
+			// The mark name is confirmed with one character.
+			input.value = string(ev.Rune())
+			return true
+		}
 		pos := stringWidth(input.value, input.cursorX+1)
 		runes := []rune(input.value)
 		input.value = string(runes[:pos])
@@ -195,6 +241,20 @@ func NewInput() *Input {
 	i.GoCandidate = &candidate{
 		list: []string{},
 	}
+	i.JSONCandidate = &candidate{
+		list: []string{
+			jsonOff,
+			jsonPretty,
+			JSONColor,
+		},
+	}
+	i.SectionCandidate = &candidate{
+		list: []string{
+			"^commit ",
+			"^[A-Z]",
+			"^#+ ",
+		},
+	}
 	i.DelimiterCandidate = &candidate{
 		list: []string{
 			"│",
@@ -214,6 +274,12 @@ func NewInput() *Input {
 	i.SearchCandidate = &candidate{
 		list: []string{},
 	}
+	i.PipeCandidate = &candidate{
+		list: []string{},
+	}
+	i.OpenCandidate = &candidate{
+		list: []string{},
+	}
 	i.EventInput = &normalInput{}
 	return &i
 }
@@ -234,6 +300,22 @@ func (root *Root) setBackSearchMode() {
 	input.EventInput = newBackSearchInput(input.SearchCandidate)
 }
 
+func (root *Root) setFilterMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Filter
+	input.EventInput = newFilterInput(input.SearchCandidate)
+}
+
+func (root *Root) setHighlightMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Highlight
+	input.EventInput = newHighlightInput(&candidate{list: root.highlightWords()})
+}
+
 func (root *Root) setDelimiterMode() {
 	input := root.input
 	input.value = ""
@@ -250,6 +332,42 @@ func (root *Root) setHeaderMode() {
 	input.EventInput = newHeaderInput()
 }
 
+func (root *Root) setFrozenMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Frozen
+	input.EventInput = newFrozenInput()
+}
+
+func (root *Root) setSortMode() {
+	if !root.Doc.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Sort
+	input.EventInput = newSortInput(root.Doc.columnNum)
+}
+
+func (root *Root) setJSONInputMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = JSON
+	input.EventInput = newJSONInput(input.JSONCandidate)
+}
+
+func (root *Root) setSectionDelimiterMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Section
+	input.EventInput = newSectionDelimiterInput(input.SectionCandidate)
+}
+
 func (root *Root) setTabWidthMode() {
 	input := root.input
 	input.value = ""
@@ -381,6 +499,283 @@ func (b *backSearchInput) Down(str string) string {
 	return b.clist.down()
 }
 
+// filterInput represents the filter input mode.
+type filterInput struct {
+	value string
+	clist *candidate
+	tcell.EventTime
+}
+
+// newFilterInput returns FilterInput.
+func newFilterInput(clist *candidate) *filterInput {
+	return &filterInput{clist: clist}
+}
+
+// Prompt returns the prompt string in the input field.
+func (f *filterInput) Prompt() string {
+	return "&"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (f *filterInput) Confirm(str string) tcell.Event {
+	f.value = str
+	f.clist.list = toLast(f.clist.list, str)
+	f.clist.p = 0
+	f.SetEventNow()
+	return f
+}
+
+// Up returns strings when the up key is pressed during input.
+func (f *filterInput) Up(str string) string {
+	return f.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (f *filterInput) Down(str string) string {
+	return f.clist.down()
+}
+
+// highlightInput represents the highlight input mode.
+type highlightInput struct {
+	value string
+	clist *candidate
+	tcell.EventTime
+}
+
+// newHighlightInput returns HighlightInput.
+func newHighlightInput(clist *candidate) *highlightInput {
+	return &highlightInput{clist: clist}
+}
+
+// Prompt returns the prompt string in the input field.
+func (h *highlightInput) Prompt() string {
+	return "Highlight:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (h *highlightInput) Confirm(str string) tcell.Event {
+	h.value = str
+	h.SetEventNow()
+	return h
+}
+
+// Up returns strings when the up key is pressed during input.
+func (h *highlightInput) Up(str string) string {
+	return h.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (h *highlightInput) Down(str string) string {
+	return h.clist.down()
+}
+
+// markInput represents the mark name input mode.
+type markInput struct {
+	value string
+	tcell.EventTime
+}
+
+// newMarkInput returns MarkInput.
+func newMarkInput() *markInput {
+	return &markInput{}
+}
+
+// Prompt returns the prompt string in the input field.
+func (m *markInput) Prompt() string {
+	return "Mark:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (m *markInput) Confirm(str string) tcell.Event {
+	m.value = str
+	m.SetEventNow()
+	return m
+}
+
+// Up returns strings when the up key is pressed during input.
+func (m *markInput) Up(str string) string {
+	return ""
+}
+
+// Down returns strings when the down key is pressed during input.
+func (m *markInput) Down(str string) string {
+	return ""
+}
+
+// jumpMarkInput represents the input mode of the mark name to jump to.
+type jumpMarkInput struct {
+	value string
+	tcell.EventTime
+}
+
+// newJumpMarkInput returns JumpMarkInput.
+func newJumpMarkInput() *jumpMarkInput {
+	return &jumpMarkInput{}
+}
+
+// Prompt returns the prompt string in the input field.
+func (j *jumpMarkInput) Prompt() string {
+	return "Jump to mark:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (j *jumpMarkInput) Confirm(str string) tcell.Event {
+	j.value = str
+	j.SetEventNow()
+	return j
+}
+
+// Up returns strings when the up key is pressed during input.
+func (j *jumpMarkInput) Up(str string) string {
+	return ""
+}
+
+// Down returns strings when the down key is pressed during input.
+func (j *jumpMarkInput) Down(str string) string {
+	return ""
+}
+
+// saveInput represents the input mode of the file name to save.
+type saveInput struct {
+	value string
+	tcell.EventTime
+}
+
+// newSaveInput returns SaveInput.
+func newSaveInput() *saveInput {
+	return &saveInput{}
+}
+
+// Prompt returns the prompt string in the input field.
+func (s *saveInput) Prompt() string {
+	return "Save [range] file:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (s *saveInput) Confirm(str string) tcell.Event {
+	s.value = str
+	s.SetEventNow()
+	return s
+}
+
+// Up returns strings when the up key is pressed during input.
+func (s *saveInput) Up(str string) string {
+	return ""
+}
+
+// Down returns strings when the down key is pressed during input.
+func (s *saveInput) Down(str string) string {
+	return ""
+}
+
+// pipeInput represents the input mode of the command to pipe.
+type pipeInput struct {
+	value string
+	// selected is the range selected by the mouse.
+	selected []byte
+	clist    *candidate
+	tcell.EventTime
+}
+
+// newPipeInput returns PipeInput.
+func newPipeInput(clist *candidate, selected []byte) *pipeInput {
+	return &pipeInput{clist: clist, selected: selected}
+}
+
+// Prompt returns the prompt string in the input field.
+func (p *pipeInput) Prompt() string {
+	if p.selected != nil {
+		return "Pipe selection to:"
+	}
+	return "Pipe ['mark] to:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (p *pipeInput) Confirm(str string) tcell.Event {
+	p.value = str
+	p.clist.list = toLast(p.clist.list, str)
+	p.clist.p = 0
+	p.SetEventNow()
+	return p
+}
+
+// Up returns strings when the up key is pressed during input.
+func (p *pipeInput) Up(str string) string {
+	return p.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (p *pipeInput) Down(str string) string {
+	return p.clist.down()
+}
+
+// completer is an EventInput that completes the input with the tab key.
+type completer interface {
+	complete(str string) string
+}
+
+// openInput represents the input mode of the file name to open.
+type openInput struct {
+	value string
+	clist *candidate
+	// matches are the candidates of the completion,
+	// n is the one selected by repeating the tab key,
+	// and last is the last completed string.
+	matches []string
+	n       int
+	last    string
+	tcell.EventTime
+}
+
+// newOpenInput returns OpenInput.
+func newOpenInput(clist *candidate) *openInput {
+	return &openInput{clist: clist}
+}
+
+// Prompt returns the prompt string in the input field.
+func (o *openInput) Prompt() string {
+	return "Open file:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (o *openInput) Confirm(str string) tcell.Event {
+	o.value = str
+	o.clist.list = toLast(o.clist.list, str)
+	o.clist.p = 0
+	o.SetEventNow()
+	return o
+}
+
+// Up returns strings when the up key is pressed during input.
+func (o *openInput) Up(str string) string {
+	return o.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (o *openInput) Down(str string) string {
+	return o.clist.down()
+}
+
+// complete completes the path.
+// The input is extended to the common prefix of the candidates,
+// then the candidates are selected in order by repeating the tab key.
+func (o *openInput) complete(str string) string {
+	if str != o.last || len(o.matches) <= 1 {
+		o.matches = completePath(str)
+		o.n = -1
+		if len(o.matches) == 0 {
+			return str
+		}
+		o.last = commonPrefix(o.matches)
+		if o.last != str || len(o.matches) == 1 {
+			return o.last
+		}
+	}
+	o.n = (o.n + 1) % len(o.matches)
+	o.last = o.matches[o.n]
+	return o.last
+}
+
 // gotoInput represents the goto input mode.
 type gotoInput struct {
 	value string
@@ -458,6 +853,117 @@ func (h *headerInput) Down(str string) string {
 	return strconv.Itoa(n - 1)
 }
 
+// frozenInput represents the frozen columns input mode.
+type frozenInput struct {
+	value string
+	tcell.EventTime
+}
+
+// newFrozenInput returns frozenInput.
+func newFrozenInput() *frozenInput {
+	return &frozenInput{}
+}
+
+// Prompt returns the prompt string in the input field.
+func (f *frozenInput) Prompt() string {
+	return "Frozen columns (N or Nw):"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (f *frozenInput) Confirm(str string) tcell.Event {
+	f.value = str
+	f.SetEventNow()
+	return f
+}
+
+// Up returns strings when the up key is pressed during input.
+func (f *frozenInput) Up(str string) string {
+	n, err := strconv.Atoi(str)
+	if err != nil {
+		return "0"
+	}
+	return strconv.Itoa(n + 1)
+}
+
+// Down returns strings when the down key is pressed during input.
+func (f *frozenInput) Down(str string) string {
+	n, err := strconv.Atoi(str)
+	if err != nil || n <= 0 {
+		return "0"
+	}
+	return strconv.Itoa(n - 1)
+}
+
+// sortInput represents the sort input mode.
+type sortInput struct {
+	value  string
+	column int
+	tcell.EventTime
+}
+
+// newSortInput returns sortInput.
+func newSortInput(column int) *sortInput {
+	return &sortInput{column: column}
+}
+
+// Prompt returns the prompt string in the input field.
+func (s *sortInput) Prompt() string {
+	return "Sort column " + strconv.Itoa(s.column+1) + " (n:numeric r:reverse):"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (s *sortInput) Confirm(str string) tcell.Event {
+	s.value = str
+	s.SetEventNow()
+	return s
+}
+
+// Up returns strings when the up key is pressed during input.
+func (s *sortInput) Up(str string) string {
+	return str
+}
+
+// Down returns strings when the down key is pressed during input.
+func (s *sortInput) Down(str string) string {
+	return str
+}
+
+// jsonInput represents the JSON mode input mode.
+type jsonInput struct {
+	value string
+	clist *candidate
+	tcell.EventTime
+}
+
+// newJSONInput returns jsonInput.
+func newJSONInput(clist *candidate) *jsonInput {
+	return &jsonInput{clist: clist}
+}
+
+// Prompt returns the prompt string in the input field.
+func (j *jsonInput) Prompt() string {
+	return "JSON (color, pretty, off or fields):"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (j *jsonInput) Confirm(str string) tcell.Event {
+	j.value = str
+	j.clist.list = toLast(j.clist.list, str)
+	j.clist.p = 0
+	j.SetEventNow()
+	return j
+}
+
+// Up returns strings when the up key is pressed during input.
+func (j *jsonInput) Up(str string) string {
+	return j.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (j *jsonInput) Down(str string) string {
+	return j.clist.down()
+}
+
 // delimiterInput represents the delimiter input mode.
 type delimiterInput struct {
 	value string
@@ -494,6 +1000,42 @@ func (d *delimiterInput) Down(str string) string {
 	return d.clist.down()
 }
 
+// sectionDelimiterInput represents the section delimiter input mode.
+type sectionDelimiterInput struct {
+	value string
+	clist *candidate
+	tcell.EventTime
+}
+
+// newSectionDelimiterInput returns sectionDelimiterInput.
+func newSectionDelimiterInput(clist *candidate) *sectionDelimiterInput {
+	return &sectionDelimiterInput{clist: clist}
+}
+
+// Prompt returns the prompt string in the input field.
+func (s *sectionDelimiterInput) Prompt() string {
+	return "Section delimiter (regexp):"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (s *sectionDelimiterInput) Confirm(str string) tcell.Event {
+	s.value = str
+	s.clist.list = toLast(s.clist.list, str)
+	s.clist.p = 0
+	s.SetEventNow()
+	return s
+}
+
+// Up returns strings when the up key is pressed during input.
+func (s *sectionDelimiterInput) Up(str string) string {
+	return s.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (s *sectionDelimiterInput) Down(str string) string {
+	return s.clist.down()
+}
+
 // tabWidthInput represents the TABWidth input mode.
 type tabWidthInput struct {
 	value string
@@ -0,0 +1,296 @@
+package oviewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// JSONColor colorizes the lines of JSON.
+	JSONColor = "color"
+	// JSONFields displays the fields of JSONFields of the lines of JSON as columns.
+	JSONFields = "fields"
+	// jsonPretty is the input to open the pretty-printed document.
+	jsonPretty = "pretty"
+	// jsonOff is the input to turn off the JSON mode.
+	jsonOff = "off"
+)
+
+var (
+	// JSONKeyStyle represents the style of the keys of JSON.
+	JSONKeyStyle = tcell.StyleDefault.Foreground(tcell.ColorAqua)
+	// JSONStringStyle represents the style of the strings of JSON.
+	JSONStringStyle = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	// JSONNumberStyle represents the style of the numbers of JSON.
+	JSONNumberStyle = tcell.StyleDefault.Foreground(tcell.ColorFuchsia)
+	// JSONLiteralStyle represents the style of true, false and null of JSON.
+	JSONLiteralStyle = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+)
+
+// jsonTokens returns the tokens of the string of JSON.
+// It also works on a part of JSON, such as a line of pretty-printed JSON.
+// A string followed by ":" is a key.
+func jsonTokens(s string) []syntaxToken {
+	var tokens []syntaxToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(s))
+			kind := syntaxString
+			k := j
+			for k < len(s) && (s[k] == ' ' || s[k] == '\t') {
+				k++
+			}
+			if k < len(s) && s[k] == ':' {
+				kind = syntaxKey
+			}
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: kind})
+			i = j
+		case c == '-' || ('0' <= c && c <= '9'):
+			j := i + 1
+			for j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0 {
+				j++
+			}
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxNumber})
+			i = j
+		case 'a' <= c && c <= 'z':
+			j := i + 1
+			for j < len(s) && 'a' <= s[j] && s[j] <= 'z' {
+				j++
+			}
+			switch s[i:j] {
+			case "true", "false", "null":
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxKeyword})
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+// colorJSON applies the styles of the tokens to the contents.
+func colorJSON(lc lineContents) {
+	str, byteMap := contentsToStr(lc)
+	for _, t := range jsonTokens(str) {
+		style := jsonStyle(t.kind)
+		for n := byteMap[t.start]; n < byteMap[t.end]; n++ {
+			lc[n].style = style
+		}
+	}
+}
+
+// jsonStyle returns the style of the kind of the token of JSON.
+func jsonStyle(kind syntaxKind) tcell.Style {
+	switch kind {
+	case syntaxKey:
+		return JSONKeyStyle
+	case syntaxNumber:
+		return JSONNumberStyle
+	case syntaxKeyword:
+		return JSONLiteralStyle
+	}
+	return JSONStringStyle
+}
+
+// isJSON returns true if the line is an object or an array of JSON.
+func isJSON(line string) bool {
+	str := strings.TrimSpace(line)
+	if !strings.HasPrefix(str, "{") && !strings.HasPrefix(str, "[") {
+		return false
+	}
+	return json.Valid([]byte(str))
+}
+
+// jsonFieldsLine returns the values of the fields of the object of JSON
+// joined by the delimiter.
+// A field can be the path of the nested objects ("a.b").
+// It returns false if the line is not an object.
+func jsonFieldsLine(line string, fields []string, delimiter string) (string, bool) {
+	str := strings.TrimSpace(line)
+	if !strings.HasPrefix(str, "{") {
+		return "", false
+	}
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return "", false
+	}
+
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		value := jsonFieldValue(obj, field)
+		value = strings.ReplaceAll(value, "\n", `\n`)
+		if strings.Contains(value, delimiter) {
+			value = `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+		}
+		values[i] = value
+	}
+	return strings.Join(values, delimiter), true
+}
+
+// jsonFieldValue returns the value of the field as a string.
+func jsonFieldValue(obj map[string]interface{}, field string) string {
+	var v interface{} = obj
+	for _, key := range strings.Split(field, ".") {
+		o, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if v, ok = o[key]; !ok {
+			return ""
+		}
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// viewLine returns the line to be displayed.
+// The fields are extracted from the line of JSON in JSONFields mode.
+func (m *Document) viewLine(lineNum int) string {
+	return m.toViewLine(m.GetLine(lineNum))
+}
+
+// toViewLine returns the line to be displayed from the line of the source.
+func (m *Document) toViewLine(line string) string {
+	if m.JSONMode != JSONFields || len(m.JSONFields) == 0 {
+		return line
+	}
+	delimiter := m.columnDelimiter()
+	if delimiter == "" {
+		delimiter = ","
+	}
+	if str, ok := jsonFieldsLine(line, m.JSONFields, delimiter); ok {
+		return str
+	}
+	return line
+}
+
+// parseLine converts the line to contents.
+// The lines of JSON are colorized in JSONColor mode,
+// and the others are highlighted by the syntax of the document.
+func (m *Document) parseLine(lineNum int, tabWidth int) lineContents {
+	line := m.viewLine(lineNum)
+	lc := parseString(line, tabWidth)
+	if m.JSONMode == JSONColor && (m.jsonPretty || isJSON(line)) {
+		colorJSON(lc)
+		return lc
+	}
+	if l := m.lexer(); l != nil {
+		str, byteMap := contentsToStr(lc)
+		// The line is highlighted from the initial state until the state is computed.
+		st, _ := m.syntaxStart(l, lineNum)
+		tokens, _ := l.tokens(str, st)
+		highlightSyntax(lc, byteMap, tokens)
+	}
+	return lc
+}
+
+// setJSONMode sets the JSON mode from the input.
+// "color" colorizes, "pretty" opens the pretty-printed document,
+// "off" turns off, and the others are the fields to display as columns.
+func (root *Root) setJSONMode(input string) {
+	input = strings.TrimSpace(input)
+	m := root.Doc
+	switch input {
+	case jsonPretty:
+		root.prettyJSON()
+		return
+	case "", jsonOff:
+		m.JSONMode = ""
+	case JSONColor:
+		m.JSONMode = JSONColor
+	default:
+		fields := strings.FieldsFunc(strings.TrimPrefix(input, JSONFields), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+		if len(fields) == 0 {
+			root.setMessage(ErrNoFields.Error())
+			return
+		}
+		m.JSONMode = JSONFields
+		m.JSONFields = fields
+	}
+	m.resetCSV(-1)
+	m.resetSyntax(-1)
+	m.ClearCache()
+	root.columnWidths = nil
+	if m.JSONMode == "" {
+		root.setMessage("Set JSON mode off")
+		return
+	}
+	root.setMessage(fmt.Sprintf("Set JSON mode %s", m.jsonModeName()))
+}
+
+// jsonModeName returns the name of the JSON mode.
+func (m *Document) jsonModeName() string {
+	if m.JSONMode == JSONFields {
+		return JSONFields + " " + strings.Join(m.JSONFields, ",")
+	}
+	return m.JSONMode
+}
+
+// prettyJSON creates a document that pretty-prints the current document and switches to it.
+func (root *Root) prettyJSON() {
+	src := root.DocList[root.CurrentDoc]
+	m, err := NewJSONPrettyDocument(src)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m.FileName = fmt.Sprintf("%s [json]", src.FileName)
+	root.insertDocument(m)
+	root.setMessage("JSON pretty")
+}
+
+// NewJSONPrettyDocument returns a document that pretty-prints the lines of JSON of src.
+// The other lines are unchanged, and the lines are colorized.
+// The document keeps updating while src is being read.
+func NewJSONPrettyDocument(src *Document) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.inherit(src)
+	m.JSONMode = JSONColor
+	m.jsonPretty = true
+	m.lineMap = make([]int, 0)
+	lines := NewSliceSource(nil)
+	m.SetSource(lines)
+	header := src.Header
+	go m.deriveLines(src, lines, func(n int, line string) {
+		if n < header || !isJSON(line) {
+			m.appendMapped(lines, line, src.lineNumber(n))
+			return
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(strings.TrimSpace(line)), "", "  "); err != nil {
+			m.appendMapped(lines, line, src.lineNumber(n))
+			return
+		}
+		for _, l := range strings.Split(buf.String(), "\n") {
+			m.appendMapped(lines, l, src.lineNumber(n))
+		}
+	})
+	return m, nil
+}
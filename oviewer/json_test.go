@@ -0,0 +1,177 @@
+package oviewer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_jsonTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []syntaxToken
+	}{
+		{
+			name: "object",
+			s:    `{"a": "b", "c": -1.5e3}`,
+			want: []syntaxToken{
+				{start: 1, end: 4, kind: syntaxKey},
+				{start: 6, end: 9, kind: syntaxString},
+				{start: 11, end: 14, kind: syntaxKey},
+				{start: 16, end: 22, kind: syntaxNumber},
+			},
+		},
+		{
+			name: "escapedQuote",
+			s:    `["a\"b", true, null]`,
+			want: []syntaxToken{
+				{start: 1, end: 7, kind: syntaxString},
+				{start: 9, end: 13, kind: syntaxKeyword},
+				{start: 15, end: 19, kind: syntaxKeyword},
+			},
+		},
+		{
+			name: "fragment",
+			s:    `  "key": false,`,
+			want: []syntaxToken{
+				{start: 2, end: 7, kind: syntaxKey},
+				{start: 9, end: 14, kind: syntaxKeyword},
+			},
+		},
+		{
+			name: "word",
+			s:    `truth`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonTokens(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jsonTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want bool
+	}{
+		{name: "object", line: `{"a":1}`, want: true},
+		{name: "array", line: ` [1, 2] `, want: true},
+		{name: "number", line: `1`, want: false},
+		{name: "broken", line: `{"a":`, want: false},
+		{name: "text", line: `plain text`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isJSON(tt.line); got != tt.want {
+				t.Errorf("isJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_jsonFieldsLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		fields []string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "fields",
+			line:   `{"time":"10:00","level":"info","n":1.50}`,
+			fields: []string{"level", "n", "time"},
+			want:   "info,1.50,10:00",
+			wantOK: true,
+		},
+		{
+			name:   "nested",
+			line:   `{"a":{"b":[1,2]},"c":null}`,
+			fields: []string{"a.b", "c", "missing"},
+			want:   `"[1,2]",null,`,
+			wantOK: true,
+		},
+		{
+			name:   "quoted",
+			line:   `{"msg":"a, \"b\"\nc"}`,
+			fields: []string{"msg"},
+			want:   `"a, ""b""\nc"`,
+			wantOK: true,
+		},
+		{
+			name:   "notObject",
+			line:   `[1,2]`,
+			fields: []string{"a"},
+			wantOK: false,
+		},
+		{
+			name:   "text",
+			line:   `plain text`,
+			fields: []string{"a"},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := jsonFieldsLine(tt.line, tt.fields, ",")
+			if ok != tt.wantOK {
+				t.Errorf("jsonFieldsLine() ok = %v, want %v", ok, tt.wantOK)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("jsonFieldsLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJSONPrettyDocument(t *testing.T) {
+	src, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	str := "{\"a\":1,\"b\":[true]}\ntext\n"
+	if err := src.ReadAll(ioutil.NopCloser(bytes.NewBufferString(str))); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewJSONPrettyDocument(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50 && !m.BufEOF(); i++ {
+		time.Sleep(followInterval)
+	}
+	wantLines := []string{
+		"{",
+		`  "a": 1,`,
+		`  "b": [`,
+		"    true",
+		"  ]",
+		"}",
+		"text",
+	}
+	wantMap := []int{0, 0, 0, 0, 0, 0, 1}
+	lines := make([]string, 0)
+	lineMap := make([]int, 0)
+	for n := 0; n < m.BufEndNum(); n++ {
+		lines = append(lines, m.GetLine(n))
+		lineMap = append(lineMap, m.lineNumber(n))
+	}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("NewJSONPrettyDocument() lines = %v, want %v", lines, wantLines)
+	}
+	if !reflect.DeepEqual(lineMap, wantMap) {
+		t.Errorf("NewJSONPrettyDocument() lineMap = %v, want %v", lineMap, wantMap)
+	}
+	if got := m.lineIndex(1); got != 6 {
+		t.Errorf("Document.lineIndex() = %v, want %v", got, 6)
+	}
+}
@@ -32,11 +32,28 @@ const (
 	actionMark           = "mark"
 	actionMoveMark       = "next_mark"
 	actionMovePrevMark   = "previous_mark"
+	actionJumpMark       = "jump_mark"
+	actionMarkList       = "mark_list"
+	actionDocList        = "document_list"
+	actionNextDiffFile   = "next_diff_file"
+	actionPrevDiffFile   = "previous_diff_file"
+	actionNextHunk       = "next_hunk"
+	actionPrevHunk       = "previous_hunk"
+	actionDiffFileList   = "diff_file_list"
+	actionNextSection    = "next_section"
+	actionPrevSection    = "previous_section"
+	actionSection        = "section_delimiter"
+	actionSectionHeader  = "section_header"
 	actionAlternate      = "alter_rows_mode"
 	actionLineNumMode    = "line_number_mode"
 	actionSearch         = "search"
 	actionWrap           = "wrap_mode"
 	actionColumnMode     = "column_mode"
+	actionColumnCSV      = "column_csv"
+	actionColumnAlign    = "column_align"
+	actionFrozen         = "frozen_columns"
+	actionSort           = "sort"
+	actionJSON           = "json"
 	actionBackSearch     = "backsearch"
 	actionDelimiter      = "delimiter"
 	actionHeader         = "header"
@@ -47,6 +64,20 @@ const (
 	actionNextDoc        = "next_doc"
 	actionPreviousDoc    = "previous_doc"
 	actionToggleMouse    = "toggle_mouse"
+	actionFollow         = "follow_mode"
+	actionReload         = "reload"
+	actionFilter         = "filter"
+	actionHighlight      = "highlight"
+	actionSearchAllDocs  = "search_all_docs"
+	actionSave           = "save"
+	actionPipe           = "pipe"
+	actionOpen           = "open"
+	actionCloseDoc       = "close_doc"
+	actionSplitH         = "split_horizontal"
+	actionSplitV         = "split_vertical"
+	actionNextPane       = "next_pane"
+	actionClosePane      = "close_pane"
+	actionSyncScroll     = "sync_scroll"
 )
 
 func (root *Root) setHandler() map[string]func() {
@@ -75,10 +106,27 @@ func (root *Root) setHandler() map[string]func() {
 		actionColumnMode:     root.toggleColumnMode,
 		actionAlternate:      root.toggleAlternateRows,
 		actionLineNumMode:    root.toggleLineNumMode,
-		actionMark:           root.markLineNum,
+		actionMark:           root.setMarkMode,
+		actionJumpMark:       root.setJumpMarkMode,
+		actionMarkList:       root.markList,
+		actionDocList:        root.docList,
+		actionNextDiffFile:   root.nextDiffFile,
+		actionPrevDiffFile:   root.previousDiffFile,
+		actionNextHunk:       root.nextHunk,
+		actionPrevHunk:       root.previousHunk,
+		actionDiffFileList:   root.diffFileList,
+		actionNextSection:    root.nextSection,
+		actionPrevSection:    root.previousSection,
+		actionSection:        root.setSectionDelimiterMode,
+		actionSectionHeader:  root.toggleSectionHeader,
 		actionSearch:         root.setSearchMode,
 		actionBackSearch:     root.setBackSearchMode,
 		actionDelimiter:      root.setDelimiterMode,
+		actionColumnCSV:      root.toggleColumnCSV,
+		actionColumnAlign:    root.toggleColumnAlign,
+		actionFrozen:         root.setFrozenMode,
+		actionSort:           root.setSortMode,
+		actionJSON:           root.setJSONInputMode,
 		actionHeader:         root.setHeaderMode,
 		actionTabWidth:       root.setTabWidthMode,
 		actionGoLine:         root.setGoLineMode,
@@ -87,6 +135,20 @@ func (root *Root) setHandler() map[string]func() {
 		actionNextDoc:        root.nextDoc,
 		actionPreviousDoc:    root.previousDoc,
 		actionToggleMouse:    root.toggleMouse,
+		actionFollow:         root.toggleFollowMode,
+		actionReload:         root.reload,
+		actionFilter:         root.setFilterMode,
+		actionHighlight:      root.setHighlightMode,
+		actionSearchAllDocs:  root.toggleSearchAllDocs,
+		actionSave:           root.setSaveMode,
+		actionPipe:           root.setPipeMode,
+		actionOpen:           root.setOpenMode,
+		actionCloseDoc:       root.closeCurrentDocument,
+		actionSplitH:         func() { root.splitPane(false) },
+		actionSplitV:         func() { root.splitPane(true) },
+		actionNextPane:       root.nextPane,
+		actionClosePane:      root.closePane,
+		actionSyncScroll:     root.toggleSyncScroll,
 	}
 }
 
@@ -121,9 +183,26 @@ func GetKeyBinds(bind map[string][]string) map[string][]string {
 		actionAlternate:      {"C"},
 		actionLineNumMode:    {"G"},
 		actionMark:           {"m"},
+		actionJumpMark:       {"'"},
+		actionMarkList:       {"M"},
+		actionDocList:        {"L"},
+		actionNextDiffFile:   {"}"},
+		actionPrevDiffFile:   {"{"},
+		actionNextHunk:       {")"},
+		actionPrevHunk:       {"("},
+		actionDiffFileList:   {"P"},
+		actionNextSection:    {"alt+n"},
+		actionPrevSection:    {"alt+p"},
+		actionSection:        {"alt+d"},
+		actionSectionHeader:  {"alt+h"},
 		actionSearch:         {"/"},
 		actionBackSearch:     {"?"},
 		actionDelimiter:      {"d"},
+		actionColumnCSV:      {"D"},
+		actionColumnAlign:    {"A"},
+		actionFrozen:         {"Z"},
+		actionSort:           {"s"},
+		actionJSON:           {"J"},
 		actionHeader:         {"H"},
 		actionTabWidth:       {"t"},
 		actionGoLine:         {"g"},
@@ -132,6 +211,20 @@ func GetKeyBinds(bind map[string][]string) map[string][]string {
 		actionNextDoc:        {"]"},
 		actionPreviousDoc:    {"["},
 		actionToggleMouse:    {"ctrl+alt+r"},
+		actionFollow:         {"F"},
+		actionReload:         {"R", "F5"},
+		actionFilter:         {"&"},
+		actionHighlight:      {"*"},
+		actionSearchAllDocs:  {"ctrl+alt+a"},
+		actionSave:           {"S"},
+		actionPipe:           {"|"},
+		actionOpen:           {"O"},
+		actionCloseDoc:       {"ctrl+w"},
+		actionSplitH:         {"ctrl+alt+s"},
+		actionSplitV:         {"ctrl+alt+v"},
+		actionNextPane:       {"Tab"},
+		actionClosePane:      {"ctrl+alt+w"},
+		actionSyncScroll:     {"ctrl+alt+y"},
 	}
 
 	for k, v := range bind {
@@ -188,6 +281,9 @@ func KeyBindString(k KeyBind) string {
 	k.writeKeyBind(&b, actionHelp, "display help screen")
 	k.writeKeyBind(&b, actionLogDoc, "display log screen")
 	k.writeKeyBind(&b, actionSync, "screen sync")
+	k.writeKeyBind(&b, actionReload, "reload file")
+	k.writeKeyBind(&b, actionSave, "save to file ([range] [!]file, ctrl+a: strip escapes)")
+	k.writeKeyBind(&b, actionPipe, "pipe the document, selection or ['mark] to a command")
 	k.writeKeyBind(&b, actionToggleMouse, "enable/disable mouse")
 
 	fmt.Fprintf(&b, "\n\tMoving\n\n")
@@ -203,31 +299,63 @@ func KeyBindString(k KeyBind) string {
 	k.writeKeyBind(&b, actionMoveRight, "scroll to right")
 	k.writeKeyBind(&b, actionMoveHfLeft, "scroll left half screen")
 	k.writeKeyBind(&b, actionMoveHfRight, "scroll right half screen")
-	k.writeKeyBind(&b, actionGoLine, "number of go to line")
+	k.writeKeyBind(&b, actionGoLine, "go to line (N, +N, -N, N%, bN)")
+	k.writeKeyBind(&b, actionNextSection, "move to next section")
+	k.writeKeyBind(&b, actionPrevSection, "move to previous section")
 	k.writeKeyBind(&b, actionNextDoc, "next document")
 	k.writeKeyBind(&b, actionPreviousDoc, "previous document")
+	k.writeKeyBind(&b, actionDocList, "display document list (Enter: select, x: close)")
+	k.writeKeyBind(&b, actionOpen, "open a file (Tab: complete)")
+	k.writeKeyBind(&b, actionCloseDoc, "close the current document")
+
+	fmt.Fprintf(&b, "\n\tPanes\n\n")
+	k.writeKeyBind(&b, actionSplitH, "split the pane horizontally")
+	k.writeKeyBind(&b, actionSplitV, "split the pane vertically")
+	k.writeKeyBind(&b, actionNextPane, "move the focus to the next pane")
+	k.writeKeyBind(&b, actionClosePane, "close the pane")
+	k.writeKeyBind(&b, actionSyncScroll, "enable/disable synchronized scrolling")
 
 	fmt.Fprintf(&b, "\n\tMark position\n\n")
-	k.writeKeyBind(&b, actionMark, "mark current position")
+	k.writeKeyBind(&b, actionMark, "set mark {a-zA-Z} to current position")
+	k.writeKeyBind(&b, actionJumpMark, "jump to mark {a-zA-Z}, ' to previous position")
 	k.writeKeyBind(&b, actionMoveMark, "move to next marked position")
 	k.writeKeyBind(&b, actionMovePrevMark, "move to previous marked position")
+	k.writeKeyBind(&b, actionMarkList, "display mark list")
+
+	fmt.Fprintf(&b, "\n\tDiff\n\n")
+	k.writeKeyBind(&b, actionNextDiffFile, "move to next file of the diff")
+	k.writeKeyBind(&b, actionPrevDiffFile, "move to previous file of the diff")
+	k.writeKeyBind(&b, actionNextHunk, "move to next hunk")
+	k.writeKeyBind(&b, actionPrevHunk, "move to previous hunk")
+	k.writeKeyBind(&b, actionDiffFileList, "display file list of the diff (Enter: select)")
 
 	fmt.Fprintf(&b, "\n\tSearch\n\n")
 	k.writeKeyBind(&b, actionSearch, "forward search mode")
 	k.writeKeyBind(&b, actionBackSearch, "backward search mode")
 	k.writeKeyBind(&b, actionNextSearch, "repeat forward search")
 	k.writeKeyBind(&b, actionNextBackSearch, "repeat backward search")
+	k.writeKeyBind(&b, actionSearchAllDocs, "search all documents toggle")
+	k.writeKeyBind(&b, actionFilter, "filter mode (!pattern to invert)")
+	k.writeKeyBind(&b, actionHighlight, "add/remove highlight pattern")
+	k.writeKeyBind(&b, actionSort, "sort by the column (n:numeric r:reverse)")
 
 	fmt.Fprintf(&b, "\n\tChange display\n\n")
 	k.writeKeyBind(&b, actionWrap, "wrap/nowrap toggle")
 	k.writeKeyBind(&b, actionColumnMode, "column mode toggle")
+	k.writeKeyBind(&b, actionColumnCSV, "CSV parsing of columns toggle")
+	k.writeKeyBind(&b, actionColumnAlign, "align columns toggle")
 	k.writeKeyBind(&b, actionAlternate, "color to alternate rows toggle")
 	k.writeKeyBind(&b, actionLineNumMode, "line number toggle")
+	k.writeKeyBind(&b, actionFollow, "follow mode toggle")
+	k.writeKeyBind(&b, actionSectionHeader, "sticky section heading toggle")
 
 	fmt.Fprintf(&b, "\n\tChange Display with Input\n\n")
-	k.writeKeyBind(&b, actionDelimiter, "delimiter string")
+	k.writeKeyBind(&b, actionDelimiter, "delimiter string (\\t for TAB)")
 	k.writeKeyBind(&b, actionHeader, "number of header lines")
+	k.writeKeyBind(&b, actionSection, "section delimiter (regexp)")
+	k.writeKeyBind(&b, actionFrozen, "frozen columns (N columns, Nw cells)")
 	k.writeKeyBind(&b, actionTabWidth, "TAB width")
+	k.writeKeyBind(&b, actionJSON, "JSON mode (color, pretty, off or fields)")
 
 	return b.String()
 }
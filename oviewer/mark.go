@@ -0,0 +1,203 @@
+package oviewer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// previousMark is the mark that holds the position before the last jump.
+const previousMark = '\''
+
+// markWidth is the width of the marks in the gutter.
+const markWidth = 2
+
+// validMarkName returns true if the name can be used as a mark.
+func validMarkName(name rune) bool {
+	return (name >= 'a' && name <= 'z') || (name >= 'A' && name <= 'Z') || name == previousMark
+}
+
+// setMark stores the line as the mark of the name.
+func (m *Document) setMark(name rune, lineNum int) {
+	if m.marks == nil {
+		m.marks = make(map[rune]int)
+	}
+	m.marks[name] = lineNum
+}
+
+// markNames returns the names of the marks in sorted order.
+func (m *Document) markNames() []rune {
+	names := make([]rune, 0, len(m.marks))
+	for name := range m.marks {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// markOf returns the name of the mark on the line.
+// If there are multiple marks on the line, the first name is returned.
+func (m *Document) markOf(lineNum int) (rune, bool) {
+	for _, name := range m.markNames() {
+		if name == previousMark {
+			continue
+		}
+		if m.marks[name] == lineNum {
+			return name, true
+		}
+	}
+	return 0, false
+}
+
+// hasMarks returns true if the document has named marks.
+func (m *Document) hasMarks() bool {
+	for name := range m.marks {
+		if name != previousMark {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMarkLine returns the nearest marked line after (or before) lineNum.
+func (m *Document) nextMarkLine(lineNum int, forward bool) (int, bool) {
+	found := false
+	next := 0
+	for name, n := range m.marks {
+		if name == previousMark {
+			continue
+		}
+		if forward && n > lineNum && (!found || n < next) {
+			next, found = n, true
+		}
+		if !forward && n < lineNum && (!found || n > next) {
+			next, found = n, true
+		}
+	}
+	return next, found
+}
+
+// setMarkMode waits for the name of the mark to set.
+func (root *Root) setMarkMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Mark
+	input.EventInput = newMarkInput()
+}
+
+// setJumpMarkMode waits for the name of the mark to jump to.
+func (root *Root) setJumpMarkMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = JumpMark
+	input.EventInput = newJumpMarkInput()
+}
+
+// setMark sets the mark of the input name on the current line.
+func (root *Root) setMark(input string) {
+	name, ok := markName(input)
+	if !ok || name == previousMark {
+		root.setMessage(fmt.Sprintf("%s [%s]", ErrInvalidMark, input))
+		return
+	}
+	lineNum := root.Doc.lineNum + root.Doc.Header
+	root.Doc.setMark(name, lineNum)
+	root.prepareStartX()
+	root.setMessage(fmt.Sprintf("Set mark %c to line %d", name, root.Doc.lineNumber(lineNum)-root.Doc.Header+1))
+}
+
+// jumpMark moves to the line of the input mark name.
+func (root *Root) jumpMark(input string) {
+	if root.Doc != root.DocList[root.CurrentDoc] {
+		root.toNormal()
+	}
+	name, ok := markName(input)
+	if !ok {
+		root.setMessage(fmt.Sprintf("%s [%s]", ErrInvalidMark, input))
+		return
+	}
+	lineNum, ok := root.Doc.marks[name]
+	if !ok {
+		root.setMessage(fmt.Sprintf("mark %c %s", name, ErrNotFound))
+		return
+	}
+	root.Doc.setMark(previousMark, root.Doc.lineNum+root.Doc.Header)
+	root.moveLine(lineNum - root.Doc.Header)
+	root.setMessage(fmt.Sprintf("Moved to mark %c", name))
+}
+
+// markNext moves to the next marked line.
+func (root *Root) markNext() {
+	root.moveMark(true)
+}
+
+// markPrev moves to the previous marked line.
+func (root *Root) markPrev() {
+	root.moveMark(false)
+}
+
+func (root *Root) moveMark(forward bool) {
+	lineNum, ok := root.Doc.nextMarkLine(root.Doc.lineNum+root.Doc.Header, forward)
+	if !ok {
+		root.setMessage(fmt.Sprintf("mark %s", ErrNotFound))
+		return
+	}
+	root.moveLine(lineNum - root.Doc.Header)
+	name, _ := root.Doc.markOf(lineNum)
+	root.setMessage(fmt.Sprintf("Moved to mark %c", name))
+}
+
+// markName returns the mark name of the input.
+func markName(input string) (rune, bool) {
+	runes := []rune(input)
+	if len(runes) != 1 || !validMarkName(runes[0]) {
+		return 0, false
+	}
+	return runes[0], true
+}
+
+// markList is to switch between the mark list screen and normal screen.
+func (root *Root) markList() {
+	if root.input.mode == MarkList {
+		root.toNormal()
+		return
+	}
+	root.toMarkList()
+}
+
+func (root *Root) toMarkList() {
+	doc, err := NewMarkListDoc(root.DocList[root.CurrentDoc])
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.setDocument(doc)
+	root.input.mode = MarkList
+}
+
+// NewMarkListDoc generates a document that lists the marks of the document.
+func NewMarkListDoc(m *Document) (*Document, error) {
+	doc, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	doc.FileName = fmt.Sprintf("Marks [%s]", m.FileName)
+	doc.Header = 1
+	lines := []string{"mark   line  text"}
+	for _, name := range m.markNames() {
+		lineNum := m.marks[name]
+		text := ""
+		if lineNum < m.BufEndNum() {
+			text = strings.TrimRight(m.GetLine(lineNum), "\n")
+		}
+		lines = append(lines, fmt.Sprintf(" %c  %7d  %s", name, m.lineNumber(lineNum)-m.Header+1, text))
+	}
+	src := NewSliceSource(lines)
+	src.SetEOF(true)
+	doc.SetSource(src)
+	return doc, nil
+}
@@ -0,0 +1,104 @@
+package oviewer
+
+import "testing"
+
+func TestDocument_nextMarkLine(t *testing.T) {
+	type args struct {
+		lineNum int
+		forward bool
+	}
+	tests := []struct {
+		name   string
+		marks  map[rune]int
+		args   args
+		want   int
+		wantOK bool
+	}{
+		{
+			name:   "testForward",
+			marks:  map[rune]int{'a': 10, 'b': 3, 'c': 20},
+			args:   args{lineNum: 5, forward: true},
+			want:   10,
+			wantOK: true,
+		},
+		{
+			name:   "testBackward",
+			marks:  map[rune]int{'a': 10, 'b': 3, 'c': 20},
+			args:   args{lineNum: 15, forward: false},
+			want:   10,
+			wantOK: true,
+		},
+		{
+			name:   "testNoNext",
+			marks:  map[rune]int{'a': 10, 'b': 3},
+			args:   args{lineNum: 10, forward: true},
+			want:   0,
+			wantOK: false,
+		},
+		{
+			name:   "testSkipPrevious",
+			marks:  map[rune]int{'a': 10, previousMark: 7},
+			args:   args{lineNum: 5, forward: true},
+			want:   10,
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.marks = tt.marks
+			got, ok := m.nextMarkLine(tt.args.lineNum, tt.args.forward)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Document.nextMarkLine() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDocument_markOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		marks   map[rune]int
+		lineNum int
+		want    rune
+		wantOK  bool
+	}{
+		{
+			name:    "testMarked",
+			marks:   map[rune]int{'b': 3, 'a': 3},
+			lineNum: 3,
+			want:    'a',
+			wantOK:  true,
+		},
+		{
+			name:    "testNotMarked",
+			marks:   map[rune]int{'a': 3},
+			lineNum: 4,
+			want:    0,
+			wantOK:  false,
+		},
+		{
+			name:    "testPrevious",
+			marks:   map[rune]int{previousMark: 4},
+			lineNum: 4,
+			want:    0,
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.marks = tt.marks
+			got, ok := m.markOf(tt.lineNum)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Document.markOf() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -14,6 +14,35 @@ import (
 func (root *Root) mouseEvent(ev *tcell.EventMouse) {
 	button := ev.Buttons()
 
+	if y, ok := root.tabBarRow(); ok {
+		if x, ey := ev.Position(); ey == y {
+			if button&tcell.Button1 != 0 {
+				root.clickTab(x)
+			}
+			return
+		}
+	}
+
+	if n, ok := root.paneAt(ev.Position()); ok && n != root.paneNum {
+		switch root.input.mode {
+		case Normal, Help, LogDoc, MarkList, DocList, DiffList:
+			root.focusPane(n)
+		default:
+			return
+		}
+	}
+
+	if root.input.mode == DocList && button&tcell.Button1 != 0 {
+		_, y := root.viewPosition(ev)
+		root.clickDocList(y)
+		return
+	}
+	if root.input.mode == DiffList && button&tcell.Button1 != 0 {
+		_, y := root.viewPosition(ev)
+		root.clickDiffList(y)
+		return
+	}
+
 	if button&tcell.WheelUp != 0 {
 		root.wheelUp()
 		return
@@ -32,6 +61,16 @@ func (root *Root) mouseEvent(ev *tcell.EventMouse) {
 	root.skipDraw = true
 }
 
+// viewPosition returns the position of the mouse event in the view.
+func (root *Root) viewPosition(ev *tcell.EventMouse) (int, int) {
+	x, y := ev.Position()
+	if s, ok := root.Screen.(*viewScreen); ok {
+		x -= s.x
+		y -= s.y
+	}
+	return x, y
+}
+
 func (root *Root) wheelUp() {
 	root.setMessage("")
 	root.moveUp()
@@ -60,11 +99,11 @@ func (root *Root) selectRange(ev *tcell.EventMouse) {
 		}
 		root.mouseSelect = true
 		root.mousePressed = true
-		root.x1, root.y1 = ev.Position()
+		root.x1, root.y1 = root.viewPosition(ev)
 	}
 
 	if root.mousePressed {
-		root.x2, root.y2 = ev.Position()
+		root.x2, root.y2 = root.viewPosition(ev)
 	}
 
 	if root.mouseSelect {
@@ -149,17 +188,7 @@ func (root *Root) reverseLine(y int, start int, end int, sel bool) {
 }
 
 func (root *Root) putClipboard(ctx context.Context) {
-	y1 := root.y1
-	y2 := root.y2
-	x1 := root.x1
-	x2 := root.x2
-
-	if y2 < y1 {
-		y1, y2 = y2, y1
-		x1, x2 = x2, x1
-	}
-
-	buff, err := root.rangeToBuffer(x1, y1, x2, y2)
+	buff, err := root.selectBuffer()
 	if err != nil {
 		root.debugMessage(fmt.Sprintf("%s", err))
 		return
@@ -174,17 +203,32 @@ func (root *Root) putClipboard(ctx context.Context) {
 	root.setMessage("Copy")
 }
 
+// selectBuffer returns the string of the range selected by the mouse.
+func (root *Root) selectBuffer() (*bytes.Buffer, error) {
+	y1 := root.y1
+	y2 := root.y2
+	x1 := root.x1
+	x2 := root.x2
+
+	if y2 < y1 {
+		y1, y2 = y2, y1
+		x1, x2 = x2, x1
+	}
+
+	return root.rangeToBuffer(x1, y1, x2, y2)
+}
+
 func (root *Root) rectangleToBuffer(x1, y1, x2, y2 int) (*bytes.Buffer, error) {
 	var buff bytes.Buffer
 
 	for y := y1; y <= y2; y++ {
 		ln := root.lnumber[y]
-		lc, err := root.Doc.lineToContents(ln.line, root.Doc.TabWidth)
+		lc, err := root.displayContents(ln.line)
 		if err != nil {
 			return nil, err
 		}
 		wx := root.branchWidth(lc, ln.branch)
-		line := root.selectLine(ln.line, root.Doc.x+x1+wx, root.Doc.x+x2+wx+1)
+		line := root.selectLine(ln.line, root.contentX(ln.line, lc, x1)+wx, root.contentX(ln.line, lc, x2)+wx+1)
 
 		if _, err := buff.WriteString(line); err != nil {
 			return nil, err
@@ -204,21 +248,21 @@ func (root *Root) rangeToBuffer(x1, y1, x2, y2 int) (*bytes.Buffer, error) {
 	var buff bytes.Buffer
 
 	ln1 := root.lnumber[y1]
-	lc1, err := root.Doc.lineToContents(ln1.line, root.Doc.TabWidth)
+	lc1, err := root.displayContents(ln1.line)
 	if err != nil {
 		return nil, err
 	}
 	wx1 := root.branchWidth(lc1, ln1.branch)
 
 	ln2 := root.lnumber[y2]
-	lc2, err := root.Doc.lineToContents(ln2.line, root.Doc.TabWidth)
+	lc2, err := root.displayContents(ln2.line)
 	if err != nil {
 		return nil, err
 	}
 	wx2 := root.branchWidth(lc2, ln2.branch)
 
 	if ln1.line == ln2.line {
-		str := root.selectLine(ln1.line, root.Doc.x+x1+wx1, root.Doc.x+x2+wx2+1)
+		str := root.selectLine(ln1.line, root.contentX(ln1.line, lc1, x1)+wx1, root.contentX(ln2.line, lc2, x2)+wx2+1)
 		if len(str) == 0 {
 			return &buff, nil
 		}
@@ -228,7 +272,7 @@ func (root *Root) rangeToBuffer(x1, y1, x2, y2 int) (*bytes.Buffer, error) {
 		return &buff, nil
 	}
 
-	str := root.selectLine(ln1.line, root.Doc.x+x1+wx1, -1)
+	str := root.selectLine(ln1.line, root.contentX(ln1.line, lc1, x1)+wx1, -1)
 	if _, err := buff.WriteString(str); err != nil {
 		return nil, err
 	}
@@ -254,7 +298,7 @@ func (root *Root) rangeToBuffer(x1, y1, x2, y2 int) (*bytes.Buffer, error) {
 		}
 	}
 
-	str = root.selectLine(ln2.line, 0, root.Doc.x+x2+wx2+1)
+	str = root.selectLine(ln2.line, 0, root.contentX(ln2.line, lc2, x2)+wx2+1)
 	if _, err := buff.WriteString(str); err != nil {
 		return nil, err
 	}
@@ -282,7 +326,7 @@ func (root *Root) branchWidth(lc lineContents, branch int) int {
 }
 
 func (root *Root) selectLine(ly int, x1 int, x2 int) string {
-	lc, err := root.Doc.lineToContents(ly, root.Doc.TabWidth)
+	lc, err := root.displayContents(ly)
 	if err != nil {
 		root.debugMessage(fmt.Sprintf("%s", err))
 		return ""
@@ -330,7 +374,7 @@ func (root *Root) Paste() {
 func (root *Root) getClipboard(ctx context.Context) {
 	input := root.input
 	switch input.mode {
-	case Normal, Help, LogDoc:
+	case Normal, Help, LogDoc, MarkList, DocList, DiffList:
 		return
 	}
 
@@ -7,7 +7,7 @@ func (root *Root) moveTop() {
 
 // Go to the bottom line.
 func (root *Root) moveBottom() {
-	root.moveLine(root.Doc.endNum + 1)
+	root.moveLine(root.Doc.BufEndNum() + 1)
 }
 
 // Move to the specified line.
@@ -108,7 +108,7 @@ func (root *Root) moveDown() {
 func (root *Root) moveLeft() {
 	root.resetSelect()
 	if root.Doc.ColumnMode {
-		if root.Doc.columnNum > 0 {
+		if root.Doc.columnNum > root.Doc.FrozenColumns {
 			root.Doc.columnNum--
 			root.Doc.x = root.columnModeX()
 		}
@@ -138,18 +138,33 @@ func (root *Root) moveRight() {
 }
 
 // columnModeX returns the actual x from root.Doc.columnNum.
+// The column is displayed next to the frozen columns.
 func (root *Root) columnModeX() int {
-	lc, err := root.Doc.lineToContents(root.Doc.lineNum+root.Doc.Header, root.Doc.TabWidth)
+	m := root.Doc
+	if m.columnAligned() && root.columnWidths == nil {
+		root.columnWidths = root.measureColumns()
+	}
+	lineNum := m.lineNum + m.Header
+	lc, err := root.displayContents(lineNum)
 	if err != nil {
 		return 0
 	}
+	frozen := root.frozenWidth(lineNum, lc)
+	if m.columnAligned() {
+		x, ok := root.alignedColumnX(m.columnNum)
+		if !ok {
+			m.columnNum = m.FrozenColumns
+			x, _ = root.alignedColumnX(m.columnNum)
+		}
+		return max(x-frozen, 0)
+	}
 	lineStr, byteMap := contentsToStr(lc)
-	start, end := rangePosition(lineStr, root.Doc.ColumnDelimiter, root.Doc.columnNum)
+	start, end := m.columnRange(lineNum, lineStr, m.columnNum)
 	if start < 0 || end < 0 {
-		root.Doc.columnNum = 0
-		start, _ = rangePosition(lineStr, root.Doc.ColumnDelimiter, root.Doc.columnNum)
+		m.columnNum = m.FrozenColumns
+		start, _ = m.columnRange(lineNum, lineStr, m.columnNum)
 	}
-	return byteMap[start]
+	return max(byteMap[start]-frozen, 0)
 }
 
 // Move to the left by half a screen.
@@ -0,0 +1,126 @@
+package oviewer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// setOpenMode waits for the input of the file name to open.
+func (root *Root) setOpenMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = OpenFile
+	input.EventInput = newOpenInput(input.OpenCandidate)
+}
+
+// openDocument opens the file as a new document after the current document.
+func (root *Root) openDocument(input string) {
+	fileName := expandHome(strings.TrimSpace(input))
+	if fileName == "" {
+		root.setMessage(ErrMissingFile.Error())
+		return
+	}
+	fi, err := os.Stat(fileName)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	if fi.IsDir() {
+		root.setMessage(fmt.Sprintf("%s: %s", fileName, ErrIsDirectory))
+		return
+	}
+
+	m, err := NewDocument()
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m.setStatus(root.Config.Status)
+	if err := m.ReadFile(fileName); err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.restoreFile(m)
+	log.Printf("open %s", m.FileName)
+	root.insertDocument(m)
+	root.setMessage(fmt.Sprintf("Open %s", m.FileName))
+}
+
+// closeCurrentDocument closes the current document.
+func (root *Root) closeCurrentDocument() {
+	fileName := root.DocList[root.CurrentDoc].FileName
+	if err := root.closeDocument(root.CurrentDoc); err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.switchDocument(root.CurrentDoc)
+	root.setMessage(fmt.Sprintf("Close %s", fileName))
+}
+
+// expandHome replaces the leading "~/" of the path with the home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// completePath returns the paths that start with str.
+// Directories end with a separator.
+// Hidden files are included only if the name starts with ".".
+func completePath(str string) []string {
+	dir, base := filepath.Split(str)
+	readDir := expandHome(dir)
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := ioutil.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	var matches []string
+	for _, fi := range entries {
+		name := fi.Name()
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		path := dir + name
+		if fi.IsDir() {
+			path += string(filepath.Separator)
+		} else if fi.Mode()&os.ModeSymlink != 0 {
+			if st, err := os.Stat(filepath.Join(readDir, name)); err == nil && st.IsDir() {
+				path += string(filepath.Separator)
+			}
+		}
+		matches = append(matches, path)
+	}
+	return matches
+}
+
+// commonPrefix returns the longest common prefix of the list.
+func commonPrefix(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	prefix := list[0]
+	for _, s := range list[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,105 @@
+package oviewer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testCompleteDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "ov-open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo.txt", "foo.log", "bar.txt", ".hidden"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "baz"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return dir + string(filepath.Separator)
+}
+
+func Test_completePath(t *testing.T) {
+	dir := testCompleteDir(t)
+	defer os.RemoveAll(dir)
+	sep := string(filepath.Separator)
+	tests := []struct {
+		name string
+		str  string
+		want []string
+	}{
+		{
+			name: "testPrefix",
+			str:  dir + "foo",
+			want: []string{dir + "foo.log", dir + "foo.txt"},
+		},
+		{
+			name: "testDirectory",
+			str:  dir + "ba",
+			want: []string{dir + "bar.txt", dir + "baz" + sep},
+		},
+		{
+			name: "testHidden",
+			str:  dir + ".",
+			want: []string{dir + ".hidden"},
+		},
+		{
+			name: "testNoMatch",
+			str:  dir + "qux",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completePath(tt.str); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commonPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		list []string
+		want string
+	}{
+		{name: "testEmpty", list: nil, want: ""},
+		{name: "testOne", list: []string{"foo"}, want: "foo"},
+		{name: "testCommon", list: []string{"foo.txt", "foo.log"}, want: "foo."},
+		{name: "testMultibyte", list: []string{"あいう", "あいえ"}, want: "あい"},
+		{name: "testNone", list: []string{"foo", "bar"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commonPrefix(tt.list); got != tt.want {
+				t.Errorf("commonPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenInput_complete(t *testing.T) {
+	dir := testCompleteDir(t)
+	defer os.RemoveAll(dir)
+	o := newOpenInput(&candidate{})
+	want := []string{
+		dir + "foo.",
+		dir + "foo.log",
+		dir + "foo.txt",
+		dir + "foo.log",
+	}
+	str := dir + "f"
+	for i, w := range want {
+		str = o.complete(str)
+		if str != w {
+			t.Fatalf("openInput.complete() #%d = %q, want %q", i, str, w)
+		}
+	}
+}
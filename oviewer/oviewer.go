@@ -3,6 +3,7 @@ package oviewer
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
@@ -79,6 +80,35 @@ type Root struct {
 
 	// cancelKeys represents the cancellation key string.
 	cancelKeys []string
+
+	// process is the command executed in exec mode.
+	process *execProcess
+
+	// highlights is a list of patterns highlighted in their own colors.
+	highlights []highlight
+
+	// terminal is the whole screen.
+	// tcell.Screen is the region of the focused pane in it.
+	terminal tcell.Screen
+	// panes is the list of the panes, and paneNum is the focused pane.
+	panes   []*pane
+	paneNum int
+	// vertical arranges the panes side by side if true.
+	vertical bool
+	// syncScroll scrolls the other panes with the focused pane if true.
+	syncScroll bool
+	// inactivePane is true while drawing the panes other than the focused one.
+	inactivePane bool
+
+	// tabs is the positions of the tabs on the tab bar.
+	tabs []tab
+	// listCursor is the entry of the cursor on the document list
+	// or the file list of the diff.
+	listCursor int
+	// diffListFiles is the files on the file list of the diff.
+	diffListFiles []diffFile
+	// columnWidths is the widths of the aligned columns measured at the last drawing.
+	columnWidths []int
 }
 
 type lineNumber struct {
@@ -102,6 +132,36 @@ type status struct {
 	WrapMode bool
 	// Column Delimiter
 	ColumnDelimiter string
+	// ColumnCSV parses the columns as CSV with quoted fields.
+	ColumnCSV bool
+	// ColumnQuote is the quote character of ColumnCSV.
+	ColumnQuote string
+	// ColumnEscape is the escape character of ColumnCSV.
+	// The quote is escaped by doubling it if empty.
+	ColumnEscape string
+	// ColumnAlign pads the columns to align them in column mode.
+	ColumnAlign bool
+	// FrozenColumns is the number of columns that are not scrolled horizontally.
+	FrozenColumns int
+	// FrozenWidth is the width in cells that is not scrolled horizontally.
+	// It is used if FrozenColumns is 0.
+	FrozenWidth int
+	// JSONMode is the display mode of the lines of JSON ("color" or "fields").
+	JSONMode string
+	// JSONFields is the fields displayed as columns in "fields" mode.
+	JSONFields []string
+	// DiffMode enables the navigation of a diff.
+	// A diff is also detected by the syntax or the "diff --git" header.
+	DiffMode bool
+	// Syntax is the language of the syntax highlighting.
+	// "auto" chooses it by the extension of the file name.
+	Syntax string
+	// SectionDelimiter is the regular expression of the section headings.
+	SectionDelimiter string
+	// SectionHeader pins the current section heading below the header.
+	SectionHeader bool
+	// FollowMode follows the growth of the document.
+	FollowMode bool
 }
 
 // Config represents the settings of ov.
@@ -114,6 +174,20 @@ type Config struct {
 	ColorOverStrike string
 	// OverLine color.
 	ColorOverLine string
+	// Highlight color palette.
+	ColorHighlight []string
+	// ColorJSONKey is the color of the keys of JSON.
+	ColorJSONKey string
+	// ColorJSONString is the color of the strings of JSON.
+	ColorJSONString string
+	// ColorJSONNumber is the color of the numbers of JSON.
+	ColorJSONNumber string
+	// ColorJSONLiteral is the color of true, false and null of JSON.
+	ColorJSONLiteral string
+	// SyntaxTheme is the colors of the syntax highlighting.
+	// The keys are keyword, type, string, comment, number, key, variable,
+	// added, removed, meta and hunk.
+	SyntaxTheme map[string]string
 
 	// ColorNormalBg is the normal Background color.
 	ColorNormalBg tcell.Color
@@ -128,10 +202,35 @@ type Config struct {
 	QuitSmall bool
 	// CaseSensitive is case-sensitive if true
 	CaseSensitive bool
+	// SearchAllDocs continues the search to the other documents if true.
+	SearchAllDocs bool
 	// Debug represents whether to enable the debug output.
 	Debug bool
 	// KeyBinding
 	Keybind map[string][]string
+	// Highlights is a list of patterns to highlight.
+	Highlights []string
+	// DisableHistory does not save the history between sessions.
+	DisableHistory bool
+	// HistoryFile is the path of the history file.
+	// The history is not saved if it is empty.
+	// DefaultHistoryFile() returns the path used by the ov command.
+	HistoryFile string
+	// HistorySize is the number of entries saved in each history.
+	HistorySize int
+	// StripEscapeSequence strips escape sequences and overstrikes when saving or piping.
+	StripEscapeSequence bool
+	// StatusLeft is the template of the left side of the status line.
+	StatusLeft string
+	// StatusRight is the template of the right side of the status line.
+	StatusRight string
+	// StatusLeftStyle is the style of the left side of the status line.
+	StatusLeftStyle StatusStyle
+	// StatusRightStyle is the style of the right side of the status line.
+	StatusRightStyle StatusStyle
+	// TabBar is the position of the tab bar ("top" or "bottom").
+	// The tab bar is not displayed if empty.
+	TabBar string
 }
 
 var (
@@ -143,6 +242,8 @@ var (
 	OverStrikeStyle = tcell.StyleDefault.Bold(true)
 	// OverLineStyle represents the overline underline style.
 	OverLineStyle = tcell.StyleDefault.Underline(true)
+	// MarkStyle represents the style of the mark in the gutter.
+	MarkStyle = tcell.StyleDefault.Reverse(true)
 )
 
 var (
@@ -162,6 +263,40 @@ var (
 	ErrFailedKeyBind = errors.New("failed to set keybind")
 	// ErrSignalCatch indicates that the signal has been caught.
 	ErrSignalCatch = errors.New("signal catch")
+	// ErrNotReloadable indicates that the document cannot be reloaded.
+	ErrNotReloadable = errors.New("cannot reload")
+	// ErrInvalidMark indicates an invalid mark name.
+	ErrInvalidMark = errors.New("invalid mark name")
+	// ErrFileExists indicates that the file already exists.
+	ErrFileExists = errors.New("file exists (prefix ! to overwrite)")
+	// ErrInvalidRange indicates an invalid line range.
+	ErrInvalidRange = errors.New("invalid range")
+	// ErrNotWritable indicates that the source of the document is not writable.
+	ErrNotWritable = errors.New("not writable")
+	// ErrNoOffset indicates that the document does not know the byte offsets.
+	ErrNoOffset = errors.New("byte offset is not available")
+	// ErrIsDirectory indicates that the file is a directory.
+	ErrIsDirectory = errors.New("is a directory")
+	// ErrLastPane indicates that the last pane cannot be closed.
+	ErrLastPane = errors.New("cannot close the last pane")
+	// ErrSplitDirection indicates that the panes are split in the other direction.
+	ErrSplitDirection = errors.New("already split in the other direction")
+	// ErrTooSmall indicates that the screen is too small.
+	ErrTooSmall = errors.New("screen is too small")
+	// ErrLastDocument indicates that the last document cannot be closed.
+	ErrLastDocument = errors.New("cannot close the last document")
+	// ErrNotColumnMode indicates that column mode is off.
+	ErrNotColumnMode = errors.New("not in column mode")
+	// ErrNotDiffMode indicates that the document is not a diff.
+	ErrNotDiffMode = errors.New("not in diff mode")
+	// ErrNoSectionDelimiter indicates that the section delimiter is not set.
+	ErrNoSectionDelimiter = errors.New("no section delimiter")
+	// ErrInvalidSectionDelimiter indicates that the section delimiter is not a valid regular expression.
+	ErrInvalidSectionDelimiter = errors.New("invalid section delimiter")
+	// ErrInvalidSortOption indicates an invalid sort option.
+	ErrInvalidSortOption = errors.New("invalid sort option (n:numeric r:reverse)")
+	// ErrNoFields indicates that no fields are specified.
+	ErrNoFields = errors.New("no fields")
 )
 
 // NewOviewer return the structure of oviewer.
@@ -182,8 +317,12 @@ func NewOviewer(docs ...*Document) (*Root, error) {
 func NewConfig() Config {
 	return Config{
 		Status: status{
-			TabWidth: 8,
+			TabWidth:    8,
+			ColumnQuote: DefaultColumnQuote,
 		},
+		StatusLeft:      DefaultStatusLeft,
+		StatusRight:     DefaultStatusRight,
+		StatusLeftStyle: StatusStyle{Reverse: true},
 	}
 }
 
@@ -258,7 +397,7 @@ func (root *Root) SetConfig(config Config) {
 
 func (root *Root) setKeyConfig() error {
 	for _, doc := range root.DocList {
-		doc.status = root.Config.Status
+		doc.setStatus(root.Config.Status)
 	}
 
 	keyBind := GetKeyBinds(root.Config.Keybind)
@@ -289,10 +428,10 @@ func NewHelp(k KeyBind) (*Document, error) {
 	}
 	help.FileName = "Help"
 	str := KeyBindString(k)
-	help.lines = append(help.lines, "\t\t\tov help\n")
-	help.lines = append(help.lines, strings.Split(str, "\n")...)
-	help.eof = true
-	help.endNum = len(help.lines)
+	src := NewSliceSource([]string{"\t\t\tov help\n"})
+	src.Append(strings.Split(str, "\n")...)
+	src.SetEOF(true)
+	help.SetSource(src)
 	return help, err
 }
 
@@ -309,11 +448,13 @@ func NewLogDoc() (*Document, error) {
 
 // Write matches the interface of io.Writer.
 // Therefore, the log.Print output is displayed by logDoc.
+// It appends to the source if the source is io.Writer.
 func (logDoc *Document) Write(p []byte) (int, error) {
-	str := fmt.Sprintf("%s\n", string(p))
-	logDoc.lines = append(logDoc.lines, str)
-	logDoc.endNum = len(logDoc.lines)
-	return len(str), nil
+	w, ok := logDoc.src.(io.Writer)
+	if !ok {
+		return 0, ErrNotWritable
+	}
+	return w.Write(p)
 }
 
 // Run starts the terminal pager.
@@ -332,6 +473,14 @@ func (root *Root) Run() error {
 	}
 	defer root.Screen.Fini()
 
+	if root.process != nil {
+		defer func() {
+			if err := root.process.interrupt(); err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+
 	if !root.Config.DisableMouse {
 		root.Screen.EnableMouse()
 	}
@@ -348,6 +497,11 @@ func (root *Root) Run() error {
 		log.Printf("open %s", d.FileName)
 	}
 	root.setGlobalStyle()
+	root.setHighlights()
+	root.layout()
+	root.prepareView()
+	root.loadHistory()
+	defer root.saveHistory()
 	root.Screen.Clear()
 
 	root.viewSync()
@@ -369,6 +523,12 @@ func (root *Root) Run() error {
 		case <-quitChan:
 			return nil
 		case sig := <-sigs:
+			if sig == os.Interrupt && root.process != nil {
+				if err := root.process.interrupt(); err != nil {
+					log.Println(err)
+				}
+				continue
+			}
 			return fmt.Errorf("%w [%s]", ErrSignalCatch, sig)
 		}
 	}
@@ -450,6 +610,23 @@ func (root *Root) setGlobalStyle() {
 	if root.ColorOverLine != "" {
 		OverLineStyle = OverLineStyle.Foreground(tcell.GetColor(root.ColorOverLine))
 	}
+	if root.ColorJSONKey != "" {
+		JSONKeyStyle = JSONKeyStyle.Foreground(tcell.GetColor(root.ColorJSONKey))
+	}
+	if root.ColorJSONString != "" {
+		JSONStringStyle = JSONStringStyle.Foreground(tcell.GetColor(root.ColorJSONString))
+	}
+	if root.ColorJSONNumber != "" {
+		JSONNumberStyle = JSONNumberStyle.Foreground(tcell.GetColor(root.ColorJSONNumber))
+	}
+	if root.ColorJSONLiteral != "" {
+		JSONLiteralStyle = JSONLiteralStyle.Foreground(tcell.GetColor(root.ColorJSONLiteral))
+	}
+	for name, color := range root.SyntaxTheme {
+		if kind, ok := syntaxNames[strings.ToLower(name)]; ok {
+			SyntaxStyles[kind] = SyntaxStyles[kind].Foreground(tcell.GetColor(color))
+		}
+	}
 
 	_, normalBgColor, _ := tcell.StyleDefault.Decompose()
 	root.ColorNormalBg = normalBgColor
@@ -520,10 +697,10 @@ func (root *Root) setWrapHeaderLen() {
 // bottomLineNum returns the display start line
 // when the last line number as an argument.
 func (root *Root) bottomLineNum(num int) (int, int) {
-	num = min(num, root.Doc.endNum)
-	bottomLine := (root.vHight - 2)
+	num = min(num, root.Doc.BufEndNum())
+	bottomLine := (root.vHight - 2) - root.sectionHeaderLen()
 	if !root.Doc.WrapMode {
-		if num < (root.vHight - root.Doc.Header) {
+		if num < (root.vHight - root.Doc.Header - root.sectionHeaderLen()) {
 			return 0, 0
 		}
 		return num - bottomLine, 0
@@ -564,6 +741,26 @@ func (root *Root) toggleColumnMode() {
 	root.setMessage(fmt.Sprintf("Set ColumnMode %t", root.Doc.ColumnMode))
 }
 
+// toggleColumnCSV toggles ColumnCSV each time it is called.
+func (root *Root) toggleColumnCSV() {
+	root.Doc.ColumnCSV = !root.Doc.ColumnCSV
+	if root.Doc.ColumnMode {
+		root.Doc.x = root.columnModeX()
+	}
+	root.setMessage(fmt.Sprintf("Set ColumnCSV %t", root.Doc.ColumnCSV))
+}
+
+// toggleColumnAlign toggles ColumnAlign each time it is called.
+func (root *Root) toggleColumnAlign() {
+	root.Doc.ColumnAlign = !root.Doc.ColumnAlign
+	root.columnWidths = nil
+	root.Doc.x = 0
+	if root.Doc.ColumnMode {
+		root.Doc.x = root.columnModeX()
+	}
+	root.setMessage(fmt.Sprintf("Set ColumnAlign %t", root.Doc.ColumnAlign))
+}
+
 // toggleAlternateRows toggles the AlternateRows each time it is called.
 func (root *Root) toggleAlternateRows() {
 	root.Doc.ClearCache()
@@ -578,8 +775,27 @@ func (root *Root) toggleLineNumMode() {
 	root.setMessage(fmt.Sprintf("Set LineNumMode %t", root.Doc.LineNumMode))
 }
 
-// resize is a wrapper function that calls viewSync.
+// toggleFollowMode toggles FollowMode each time it is called.
+func (root *Root) toggleFollowMode() {
+	root.Doc.setFollowMode(!root.Doc.FollowMode)
+	if root.Doc.FollowMode {
+		root.followBottom()
+	}
+	root.setMessage(fmt.Sprintf("Set FollowMode %t", root.Doc.FollowMode))
+}
+
+// followBottom moves to the bottom without the EOF message.
+func (root *Root) followBottom() {
+	l, b := root.bottomLineNum(root.Doc.BufEndNum())
+	root.Doc.lineNum = l
+	root.Doc.branch = b
+}
+
+// resize lays out the panes again and calls viewSync.
 func (root *Root) resize() {
+	if root.terminal != nil {
+		root.layout()
+	}
 	root.viewSync()
 }
 
@@ -595,34 +811,21 @@ func (root *Root) viewSync() {
 func (root *Root) prepareStartX() {
 	root.startX = 0
 	if root.Doc.LineNumMode {
-		root.startX = len(fmt.Sprintf("%d", root.Doc.BufEndNum())) + 1
+		root.startX = len(fmt.Sprintf("%d", root.Doc.lastLineNumber()+1)) + 1
+	}
+	if root.Doc.hasMarks() {
+		root.startX += markWidth
 	}
 }
 
 // updateEndNum updates the last line number.
 func (root *Root) updateEndNum() {
+	root.restorePosition()
 	root.prepareStartX()
-	root.statusDraw()
-}
-
-// goLine will move to the specified line.
-func (root *Root) goLine(input string) {
-	lineNum, err := strconv.Atoi(input)
-	if err != nil {
-		root.setMessage(ErrInvalidNumber.Error())
-		return
+	if root.Doc.FollowMode {
+		root.followBottom()
 	}
-
-	root.moveLine(lineNum - root.Doc.Header - 1)
-	root.setMessage(fmt.Sprintf("Moved to line %d", lineNum))
-}
-
-// markLineNum stores the specified number of lines.
-func (root *Root) markLineNum() {
-	s := strconv.Itoa(root.Doc.lineNum + 1)
-	root.input.GoCandidate.list = toLast(root.input.GoCandidate.list, s)
-	root.input.GoCandidate.p = 0
-	root.setMessage(fmt.Sprintf("Marked to line %d", root.Doc.lineNum))
+	root.statusDraw()
 }
 
 // setHeader sets the number of lines in the header.
@@ -668,26 +871,64 @@ func (root *Root) setTabWidth(input string) {
 	root.Doc.ClearCache()
 }
 
-func (root *Root) markNext() {
-	root.goLine(newGotoInput(root.input.GoCandidate).Up(""))
-}
-
-func (root *Root) markPrev() {
-	root.goLine(newGotoInput(root.input.GoCandidate).Down(""))
+// insertDocument inserts the document after the current document
+// and switches to it.
+func (root *Root) insertDocument(m *Document) {
+	n := root.CurrentDoc + 1
+	root.DocList = append(root.DocList[:n], append([]*Document{m}, root.DocList[n:]...)...)
+	root.CurrentDoc = n
+	root.setDocument(m)
+	root.input.mode = Normal
 }
 
 func (root *Root) nextDoc() {
-	root.CurrentDoc++
-	root.CurrentDoc = min(root.CurrentDoc, len(root.DocList)-1)
-	root.setDocument(root.DocList[root.CurrentDoc])
-	root.input.mode = Normal
+	root.switchDocument(min(root.CurrentDoc+1, len(root.DocList)-1))
 }
 
 func (root *Root) previousDoc() {
-	root.CurrentDoc--
-	root.CurrentDoc = max(root.CurrentDoc, 0)
-	root.setDocument(root.DocList[root.CurrentDoc])
-	root.input.mode = Normal
+	root.switchDocument(max(root.CurrentDoc-1, 0))
+}
+
+// reload reads the current document from the file again.
+// The position and display settings are kept.
+func (root *Root) reload() {
+	if root.input.mode != Normal {
+		return
+	}
+	m := root.Doc
+	if m.filePath == "" {
+		root.setMessage(ErrNotReloadable.Error())
+		return
+	}
+
+	doc, err := NewDocument()
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	doc.setStatus(m.status)
+	if err := doc.ReadFile(m.filePath); err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	doc.FileName = m.FileName
+	doc.lineNum = m.lineNum
+	doc.branch = m.branch
+	doc.x = m.x
+	doc.columnNum = m.columnNum
+	doc.marks = m.marks
+	root.reloadDocument(m, doc)
+	m.close()
+
+	root.DocList[root.CurrentDoc] = doc
+	root.setDocument(doc)
+	root.setMessage(fmt.Sprintf("Reload %s", doc.FileName))
+}
+
+// toggleSearchAllDocs toggles the search scope each time it is called.
+func (root *Root) toggleSearchAllDocs() {
+	root.SearchAllDocs = !root.SearchAllDocs
+	root.setMessage(fmt.Sprintf("Set SearchAllDocs %t", root.SearchAllDocs))
 }
 
 func (root *Root) toggleMouse() {
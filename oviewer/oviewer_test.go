@@ -0,0 +1,140 @@
+package oviewer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestRoot_reload(t *testing.T) {
+	f, err := ioutil.TempFile("", "ov-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("foo\nbar\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.setFollowMode(true)
+	if err := m.ReadFile(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewOviewer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	root.Screen = screen
+
+	if err := ioutil.WriteFile(f.Name(), []byte("baz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root.reload()
+
+	doc := root.Doc
+	if doc == m {
+		t.Fatal("Root.reload() did not replace the document")
+	}
+	defer doc.close()
+	if root.DocList[root.CurrentDoc] != doc {
+		t.Errorf("Root.DocList[%d] is not the reloaded document", root.CurrentDoc)
+	}
+	if got := doc.BufEndNum(); got != 1 {
+		t.Errorf("Document.BufEndNum() = %d, want 1", got)
+	}
+	if got := doc.GetLine(0); got != "baz" {
+		t.Errorf("Document.GetLine(0) = %v, want baz", got)
+	}
+	if !doc.FollowMode {
+		t.Error("Document.FollowMode = false, want true")
+	}
+
+	if !m.closed() {
+		t.Error("the old document is not closed")
+	}
+	src, ok := m.src.(*FileSource)
+	if !ok {
+		t.Fatalf("the old source is %T, want *FileSource", m.src)
+	}
+	if !src.closed() {
+		t.Error("the reader of the old source is not stopped")
+	}
+}
+
+func TestRoot_reload_panes(t *testing.T) {
+	f, err := ioutil.TempFile("", "ov-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("foo\nbar\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ReadFile(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewOviewer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(80, 24)
+	root.Screen = screen
+	root.layout()
+	root.splitPane(false)
+	if len(root.panes) != 2 {
+		t.Fatalf("len(Root.panes) = %d, want 2", len(root.panes))
+	}
+	other := root.panes[0]
+	other.view.TabWidth = 4
+
+	if err := ioutil.WriteFile(f.Name(), []byte("baz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root.reload()
+	doc := root.Doc
+	defer doc.close()
+
+	if other.doc != doc || other.current != doc {
+		t.Fatal("the other pane does not display the reloaded document")
+	}
+	if other.view.TabWidth != 4 {
+		t.Errorf("the other pane TabWidth = %d, want 4", other.view.TabWidth)
+	}
+	root.focusPane(0)
+	if root.Doc != doc || root.DocList[root.CurrentDoc] != doc {
+		t.Error("Root.focusPane() did not load the reloaded document")
+	}
+	if got := root.Doc.GetLine(0); got != "baz" {
+		t.Errorf("Document.GetLine(0) = %v, want baz", got)
+	}
+	if got := root.Doc.TabWidth; got != 4 {
+		t.Errorf("Document.TabWidth = %d, want 4", got)
+	}
+}
@@ -0,0 +1,339 @@
+package oviewer
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// minPaneWidth is the minimum width of a pane.
+	minPaneWidth = 10
+	// minPaneHight is the minimum height of a pane including the status line.
+	minPaneHight = 3
+)
+
+// viewScreen is a tcell.Screen that draws into a region of the terminal.
+// The coordinates of the view are relative to the region,
+// and the contents outside the region are not drawn.
+type viewScreen struct {
+	tcell.Screen
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+// Size returns the size of the region.
+func (s *viewScreen) Size() (int, int) {
+	return s.width, s.height
+}
+
+// SetContent sets the contents of the region.
+func (s *viewScreen) SetContent(x int, y int, mainc rune, combc []rune, style tcell.Style) {
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return
+	}
+	s.Screen.SetContent(s.x+x, s.y+y, mainc, combc, style)
+}
+
+// GetContent returns the contents of the region.
+func (s *viewScreen) GetContent(x int, y int) (rune, []rune, tcell.Style, int) {
+	return s.Screen.GetContent(s.x+x, s.y+y)
+}
+
+// ShowCursor displays the cursor in the region.
+func (s *viewScreen) ShowCursor(x int, y int) {
+	s.Screen.ShowCursor(s.x+x, s.y+y)
+}
+
+// contains returns true if the position of the terminal is in the region.
+func (s *viewScreen) contains(x int, y int) bool {
+	return x >= s.x && x < s.x+s.width && y >= s.y && y < s.y+s.height
+}
+
+// docView is the position and display settings of a document in a pane.
+type docView struct {
+	status
+	lineNum   int
+	branch    int
+	x         int
+	columnNum int
+}
+
+// view returns the current view of the document.
+func (m *Document) view() docView {
+	return docView{
+		status:    m.status,
+		lineNum:   m.lineNum,
+		branch:    m.branch,
+		x:         m.x,
+		columnNum: m.columnNum,
+	}
+}
+
+// setView sets the view to the document.
+func (m *Document) setView(v docView) {
+	m.setStatus(v.status)
+	m.lineNum = v.lineNum
+	m.branch = v.branch
+	m.x = v.x
+	m.columnNum = v.columnNum
+}
+
+// pane is a region of the screen that displays a document.
+// The focused pane is drawn with the fields of Root,
+// and the other panes keep their own state here.
+type pane struct {
+	screen *viewScreen
+	// doc is the document displayed (may be the help or a list screen).
+	doc *Document
+	// current is the document of DocList selected in the pane.
+	current *Document
+	mode    InputMode
+	message string
+	view    docView
+	// prevLineNum and prevX are the position at the last drawing,
+	// used for synchronized scrolling.
+	prevLineNum int
+	prevX       int
+}
+
+// savePane saves the state of the focused pane.
+func (root *Root) savePane(p *pane) {
+	p.doc = root.Doc
+	p.current = root.DocList[root.CurrentDoc]
+	p.mode = root.input.mode
+	p.message = root.message
+	p.view = root.Doc.view()
+}
+
+// loadPane makes the pane the target of drawing and operations.
+func (root *Root) loadPane(p *pane) {
+	root.Screen = p.screen
+	root.Doc = p.doc
+	root.CurrentDoc = 0
+	for n, m := range root.DocList {
+		if m == p.current {
+			root.CurrentDoc = n
+			break
+		}
+	}
+	root.input.mode = p.mode
+	root.message = p.message
+	root.Doc.setView(p.view)
+	root.prepareView()
+	root.prepareStartX()
+}
+
+// layout divides the terminal into the tab bar and the panes.
+// The panes are arranged side by side if vertical is true,
+// and stacked otherwise.
+func (root *Root) layout() {
+	if root.terminal == nil {
+		root.terminal = root.Screen
+	}
+	if len(root.panes) == 0 {
+		root.panes = []*pane{{screen: &viewScreen{Screen: root.terminal}}}
+		root.paneNum = 0
+		root.savePane(root.panes[0])
+	}
+
+	width, height := root.terminal.Size()
+	top := 0
+	switch root.TabBar {
+	case TabBarTop:
+		top = 1
+		height--
+	case TabBarBottom:
+		height--
+	}
+
+	n := len(root.panes)
+	for i, p := range root.panes {
+		s := p.screen
+		if root.vertical {
+			// One column is the separator between panes.
+			w := (width - (n - 1)) / n
+			s.x, s.y = i*(w+1), top
+			s.width, s.height = w, height
+			if i == n-1 {
+				s.width = width - s.x
+			}
+		} else {
+			h := height / n
+			s.x, s.y = 0, top+i*h
+			s.width, s.height = width, h
+			if i == n-1 {
+				s.height = height - s.y + top
+			}
+		}
+	}
+	root.Screen = root.panes[root.paneNum].screen
+}
+
+// drawPanes draws all panes.
+// The focused pane is drawn last so that the cursor is placed there.
+func (root *Root) drawPanes() {
+	current := root.panes[root.paneNum]
+	root.savePane(current)
+	dy := current.view.lineNum - current.prevLineNum
+	dx := current.view.x - current.prevX
+
+	mouseSelect := root.mouseSelect
+	root.mouseSelect = false
+	root.inactivePane = true
+	for i, p := range root.panes {
+		if i == root.paneNum {
+			continue
+		}
+		if root.syncScroll {
+			p.view.lineNum = max(p.view.lineNum+dy, 0)
+			p.view.x = max(p.view.x+dx, root.minStartX)
+		}
+		root.loadPane(p)
+		if root.Doc.FollowMode {
+			root.followBottom()
+		}
+		root.drawView()
+		root.savePane(p)
+	}
+	root.inactivePane = false
+	root.mouseSelect = mouseSelect
+
+	root.loadPane(current)
+	root.drawView()
+	current.prevLineNum = root.Doc.lineNum
+	current.prevX = root.Doc.x
+	root.drawSeparators()
+}
+
+// drawSeparators draws the lines between the panes arranged side by side.
+func (root *Root) drawSeparators() {
+	if !root.vertical {
+		return
+	}
+	style := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	for _, p := range root.panes[:len(root.panes)-1] {
+		s := p.screen
+		for y := s.y; y < s.y+s.height; y++ {
+			root.terminal.SetContent(s.x+s.width, y, '│', nil, style)
+		}
+	}
+}
+
+// focusPane moves the focus to the pane n.
+func (root *Root) focusPane(n int) {
+	if n == root.paneNum || n < 0 || n >= len(root.panes) {
+		return
+	}
+	root.savePane(root.panes[root.paneNum])
+	root.paneNum = n
+	root.resetSelect()
+	root.loadPane(root.panes[n])
+}
+
+// paneAt returns the pane at the position of the terminal.
+func (root *Root) paneAt(x int, y int) (int, bool) {
+	for n, p := range root.panes {
+		if p.screen.contains(x, y) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// splitPane splits the focused pane into two panes
+// that display the same document.
+func (root *Root) splitPane(vertical bool) {
+	if root.terminal == nil {
+		return
+	}
+	if len(root.panes) > 1 && root.vertical != vertical {
+		root.setMessage(ErrSplitDirection.Error())
+		return
+	}
+	width, height := root.terminal.Size()
+	n := len(root.panes) + 1
+	if (vertical && (width-(n-1))/n < minPaneWidth) || (!vertical && height/n < minPaneHight) {
+		root.setMessage(ErrTooSmall.Error())
+		return
+	}
+	root.vertical = vertical
+
+	current := root.panes[root.paneNum]
+	root.savePane(current)
+	p := *current
+	p.screen = &viewScreen{Screen: root.terminal}
+	p.message = ""
+	i := root.paneNum + 1
+	root.panes = append(root.panes[:i], append([]*pane{&p}, root.panes[i:]...)...)
+	root.paneNum = i
+	root.refreshPanes()
+	root.setMessage(fmt.Sprintf("Split into %d panes", len(root.panes)))
+}
+
+// closePane closes the focused pane.
+func (root *Root) closePane() {
+	if len(root.panes) <= 1 {
+		root.setMessage(ErrLastPane.Error())
+		return
+	}
+	root.panes = append(root.panes[:root.paneNum], root.panes[root.paneNum+1:]...)
+	root.paneNum = max(root.paneNum-1, 0)
+	root.refreshPanes()
+}
+
+// nextPane moves the focus to the next pane.
+func (root *Root) nextPane() {
+	if len(root.panes) <= 1 {
+		return
+	}
+	root.focusPane((root.paneNum + 1) % len(root.panes))
+}
+
+// toggleSyncScroll toggles the synchronized scrolling of the panes.
+func (root *Root) toggleSyncScroll() {
+	root.syncScroll = !root.syncScroll
+	root.setMessage(fmt.Sprintf("Set SyncScroll %t", root.syncScroll))
+}
+
+// refreshPanes lays out the panes again and redraws the whole screen.
+func (root *Root) refreshPanes() {
+	root.layout()
+	root.loadPane(root.panes[root.paneNum])
+	root.terminal.Clear()
+	root.viewSync()
+}
+
+// replaceDocument replaces the document m displayed in the panes
+// other than the focused one with the document n.
+// It is used when m is closed.
+func (root *Root) replaceDocument(m *Document, n int) {
+	for i, p := range root.panes {
+		if i == root.paneNum || (p.current != m && p.doc != m) {
+			continue
+		}
+		p.doc = root.DocList[n]
+		p.current = root.DocList[n]
+		p.mode = Normal
+		p.view = p.doc.view()
+	}
+}
+
+// reloadDocument replaces the document m displayed in the panes
+// other than the focused one with the reloaded document doc.
+// The panes keep their own views.
+func (root *Root) reloadDocument(m *Document, doc *Document) {
+	for i, p := range root.panes {
+		if i == root.paneNum {
+			continue
+		}
+		if p.doc == m {
+			p.doc = doc
+		}
+		if p.current == m {
+			p.current = doc
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package oviewer
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestRoot_layout(t *testing.T) {
+	type region struct {
+		x, y, width, height int
+	}
+	tests := []struct {
+		name     string
+		panes    int
+		vertical bool
+		tabBar   string
+		want     []region
+	}{
+		{
+			name:  "testSingle",
+			panes: 1,
+			want:  []region{{0, 0, 80, 24}},
+		},
+		{
+			name:   "testTabBarTop",
+			panes:  1,
+			tabBar: TabBarTop,
+			want:   []region{{0, 1, 80, 23}},
+		},
+		{
+			name:  "testHorizontal",
+			panes: 3,
+			want:  []region{{0, 0, 80, 8}, {0, 8, 80, 8}, {0, 16, 80, 8}},
+		},
+		{
+			name:     "testVertical",
+			panes:    2,
+			vertical: true,
+			tabBar:   TabBarBottom,
+			want:     []region{{0, 0, 39, 23}, {40, 0, 40, 23}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, err := NewOviewer(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			screen := tcell.NewSimulationScreen("")
+			if err := screen.Init(); err != nil {
+				t.Fatal(err)
+			}
+			defer screen.Fini()
+			screen.SetSize(80, 24)
+			root.Screen = screen
+			root.TabBar = tt.tabBar
+			root.vertical = tt.vertical
+			for i := 0; i < tt.panes; i++ {
+				root.panes = append(root.panes, &pane{screen: &viewScreen{Screen: screen}})
+			}
+			root.layout()
+			for i, p := range root.panes {
+				s := p.screen
+				got := region{s.x, s.y, s.width, s.height}
+				if got != tt.want[i] {
+					t.Errorf("pane %d = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
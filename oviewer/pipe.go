@@ -0,0 +1,116 @@
+package oviewer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// setPipeMode waits for the input of the command to pipe.
+// The range selected by the mouse at this time is piped to the command.
+func (root *Root) setPipeMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Pipe
+	var selected []byte
+	if root.mouseSelect {
+		buff, err := root.selectBuffer()
+		if err != nil {
+			log.Printf("pipe: %s", err)
+		} else if buff.Len() > 0 {
+			selected = buff.Bytes()
+		}
+	}
+	input.EventInput = newPipeInput(input.PipeCandidate, selected)
+}
+
+// pipe runs the command with the current document as input,
+// and opens the output as a new document.
+// The input is the mouse selection, the range from the mark to
+// the current line ("'a command"), or the whole document.
+func (root *Root) pipe(ev *pipeInput) {
+	command := strings.TrimSpace(ev.value)
+	m := root.Doc
+	source := m.FileName
+	strip := root.StripEscapeSequence
+	write := func(w io.Writer) error {
+		_, err := m.export(w, 0, m.BufEndNum(), strip)
+		return err
+	}
+	if len(command) > 2 && command[0] == previousMark && command[2] == ' ' {
+		name, ok := markName(command[1:2])
+		lineNum, marked := m.marks[name]
+		if !ok || !marked {
+			root.setMessage(fmt.Sprintf("mark %s %s", command[1:2], ErrNotFound))
+			return
+		}
+		current := m.lineNum + m.Header
+		start, end := min(lineNum, current), max(lineNum, current)+1
+		write = func(w io.Writer) error {
+			_, err := m.export(w, start, end, strip)
+			return err
+		}
+		source = fmt.Sprintf("%s:'%c", m.FileName, name)
+		command = strings.TrimSpace(command[2:])
+	} else if ev.selected != nil {
+		write = func(w io.Writer) error {
+			_, err := io.Copy(w, bytes.NewReader(ev.selected))
+			return err
+		}
+		source = fmt.Sprintf("%s:selection", m.FileName)
+	}
+	if command == "" {
+		return
+	}
+
+	inReader, inWriter := io.Pipe()
+	go func() {
+		inWriter.CloseWithError(write(inWriter))
+	}()
+
+	doc, err := pipeCommand(command, inReader)
+	if err != nil {
+		inReader.Close()
+		root.setMessage(err.Error())
+		return
+	}
+	doc.FileName = fmt.Sprintf("%s | %s", source, command)
+	root.insertDocument(doc)
+	root.setMessage(fmt.Sprintf("Pipe to %s", command))
+}
+
+// pipeCommand runs the command in the shell with r as stdin,
+// and returns a document that reads stdout and stderr of the command.
+// r is closed when the command exits.
+func pipeCommand(command string, r io.ReadCloser) (*Document, error) {
+	doc, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := shellCommand(command)
+	cmd.Stdin = r
+	outReader, outWriter := io.Pipe()
+	cmd.Stdout = outWriter
+	cmd.Stderr = outWriter
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Printf("%s: %s", command, err)
+		}
+		r.Close()
+		outWriter.Close()
+	}()
+
+	if err := doc.ReadAll(outReader); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
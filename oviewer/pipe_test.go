@@ -0,0 +1,49 @@
+package oviewer
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		input   string
+		want    []string
+	}{
+		{
+			name:    "testSort",
+			command: "sort",
+			input:   "c\na\nb\n",
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "testNoInput",
+			command: "echo foo",
+			input:   strings.Repeat("x\n", 100000),
+			want:    []string{"foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := pipeCommand(tt.command, ioutil.NopCloser(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 50 && !m.BufEOF(); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			got := make([]string, m.BufEndNum())
+			for n := range got {
+				got[n] = m.GetLine(n)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pipeCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package oviewer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gotoKind is the kind of the position of the goto input.
+type gotoKind int
+
+const (
+	// gotoLine is a line number ("123").
+	gotoLine gotoKind = iota
+	// gotoRelative is the number of lines from the current line ("+100", "-100").
+	gotoRelative
+	// gotoPercent is a percentage of the document ("50%").
+	gotoPercent
+	// gotoByte is a byte offset ("b123456").
+	gotoByte
+)
+
+// gotoPosition is the parsed input of the goto mode.
+type gotoPosition struct {
+	kind    gotoKind
+	num     int64
+	percent float64
+}
+
+// parseGoto parses the input of the goto mode.
+func parseGoto(input string) (gotoPosition, error) {
+	input = strings.TrimSpace(input)
+	pos := gotoPosition{}
+	var err error
+	switch {
+	case strings.HasSuffix(input, "%"):
+		pos.kind = gotoPercent
+		pos.percent, err = strconv.ParseFloat(strings.TrimSpace(input[:len(input)-1]), 64)
+		if err == nil && (pos.percent < 0 || pos.percent > 100) {
+			return pos, ErrOutOfRange
+		}
+	case strings.HasPrefix(input, "+"), strings.HasPrefix(input, "-"):
+		pos.kind = gotoRelative
+		pos.num, err = strconv.ParseInt(input, 10, 64)
+	case strings.HasPrefix(input, "b"):
+		pos.kind = gotoByte
+		pos.num, err = strconv.ParseInt(input[1:], 10, 64)
+		if err == nil && pos.num < 0 {
+			return pos, ErrOutOfRange
+		}
+	default:
+		pos.kind = gotoLine
+		pos.num, err = strconv.ParseInt(input, 10, 64)
+	}
+	if err != nil {
+		return pos, ErrInvalidNumber
+	}
+	return pos, nil
+}
+
+// goLine will move to the specified position.
+// The input is a line number, a relative number of lines ("+100", "-100"),
+// a percentage ("50%") or a byte offset ("b123456").
+func (root *Root) goLine(input string) {
+	pos, err := parseGoto(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+
+	m := root.Doc
+	switch pos.kind {
+	case gotoLine:
+		root.moveLine(m.lineIndex(int(pos.num)-1) - m.Header)
+		root.setMessage(fmt.Sprintf("Moved to line %d", pos.num))
+	case gotoRelative:
+		root.moveLine(max(m.lineNum+int(pos.num), 0))
+		root.setMessage(fmt.Sprintf("Moved to line %d", m.lineNumber(m.lineNum+m.Header)-m.Header+1))
+	case gotoPercent:
+		body := m.BufEndNum() - m.Header
+		lineNum := int(float64(body) * pos.percent / 100)
+		root.moveLine(max(min(lineNum, body-1), 0))
+		root.setMessage(fmt.Sprintf("Moved to %s%%", strconv.FormatFloat(pos.percent, 'f', -1, 64)))
+	case gotoByte:
+		lineNum, ok := m.offsetLine(pos.num)
+		if !ok {
+			root.setMessage(ErrNoOffset.Error())
+			return
+		}
+		root.moveLine(max(lineNum-m.Header, 0))
+		root.setMessage(fmt.Sprintf("Moved to byte %d (line %d)", pos.num, lineNum+1))
+	}
+}
+
+// lineOffset returns the byte offset of the beginning of the line.
+// It returns false if the source does not know the byte offsets.
+func (m *Document) lineOffset(lineNum int) (int64, bool) {
+	src, ok := m.src.(OffsetSource)
+	if !ok {
+		return 0, false
+	}
+	return src.Offset(lineNum), true
+}
+
+// offsetLine returns the line that contains the byte offset.
+// It returns false if the source does not know the byte offsets.
+func (m *Document) offsetLine(offset int64) (int, bool) {
+	src, ok := m.src.(OffsetSource)
+	if !ok {
+		return 0, false
+	}
+	return src.LineAt(offset), true
+}
+
+// percent returns the position of the line in the document as a percentage.
+func (m *Document) percent(lineNum int) int {
+	endNum := m.BufEndNum()
+	if endNum <= 0 {
+		return 0
+	}
+	if lineNum+1 >= endNum {
+		return 100
+	}
+	return (lineNum + 1) * 100 / endNum
+}
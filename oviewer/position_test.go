@@ -0,0 +1,74 @@
+package oviewer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseGoto(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    gotoPosition
+		wantErr error
+	}{
+		{
+			name:  "testLine",
+			input: "123",
+			want:  gotoPosition{kind: gotoLine, num: 123},
+		},
+		{
+			name:  "testForward",
+			input: "+100",
+			want:  gotoPosition{kind: gotoRelative, num: 100},
+		},
+		{
+			name:  "testBackward",
+			input: "-100",
+			want:  gotoPosition{kind: gotoRelative, num: -100},
+		},
+		{
+			name:  "testPercent",
+			input: "50%",
+			want:  gotoPosition{kind: gotoPercent, percent: 50},
+		},
+		{
+			name:  "testPercentFraction",
+			input: " 12.5 % ",
+			want:  gotoPosition{kind: gotoPercent, percent: 12.5},
+		},
+		{
+			name:  "testByte",
+			input: "b123456",
+			want:  gotoPosition{kind: gotoByte, num: 123456},
+		},
+		{
+			name:    "testPercentOver",
+			input:   "150%",
+			wantErr: ErrOutOfRange,
+		},
+		{
+			name:    "testInvalid",
+			input:   "abc",
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name:    "testInvalidByte",
+			input:   "b",
+			wantErr: ErrInvalidNumber,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGoto(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseGoto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGoto() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,14 +1,12 @@
 package oviewer
 
 import (
-	"bufio"
 	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
 	"errors"
 	"io"
 	"io/ioutil"
-	"log"
 	"time"
 
 	"github.com/klauspost/compress/zstd"
@@ -16,87 +14,75 @@ import (
 	"github.com/ulikunitz/xz"
 )
 
-func uncompressedReader(reader io.Reader) io.ReadCloser {
+// Compressed represents the type of compression.
+type Compressed int
+
+const (
+	uncompressed Compressed = iota
+	compressGzip
+	compressBzip2
+	compressZstd
+	compressLz4
+	compressXz
+)
+
+// readCloser combines the uncompressed reader and the closer of the file.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// followInterval is the interval at which the file is checked in follow mode.
+const followInterval = 100 * time.Millisecond
+
+// uncompressedReader returns the compression type and an uncompressed reader.
+func uncompressedReader(reader io.Reader) (Compressed, io.ReadCloser) {
 	var err error
 	buf := [7]byte{}
 	n, err := io.ReadAtLeast(reader, buf[:], len(buf))
 	if err != nil {
 		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
-			return ioutil.NopCloser(bytes.NewReader(buf[:n]))
+			return uncompressed, ioutil.NopCloser(bytes.NewReader(buf[:n]))
 		}
-		return ioutil.NopCloser(bytes.NewReader(nil))
+		return uncompressed, ioutil.NopCloser(bytes.NewReader(nil))
 	}
 
 	var r io.ReadCloser
+	cFormat := uncompressed
 	rd := io.MultiReader(bytes.NewReader(buf[:n]), reader)
 
 	switch {
 	case bytes.Equal(buf[:3], []byte{0x1f, 0x8b, 0x8}):
+		cFormat = compressGzip
 		r, err = gzip.NewReader(rd)
 	case bytes.Equal(buf[:3], []byte{0x42, 0x5A, 0x68}):
+		cFormat = compressBzip2
 		r = ioutil.NopCloser(bzip2.NewReader(rd))
 	case bytes.Equal(buf[:4], []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		cFormat = compressZstd
 		var zr *zstd.Decoder
 		zr, err = zstd.NewReader(rd)
 		r = ioutil.NopCloser(zr)
 	case bytes.Equal(buf[:4], []byte{0x04, 0x22, 0x4d, 0x18}):
+		cFormat = compressLz4
 		r = ioutil.NopCloser(lz4.NewReader(rd))
 	case bytes.Equal(buf[:7], []byte{0xfd, 0x37, 0x7a, 0x58, 0x5a, 0x0, 0x0}):
+		cFormat = compressXz
 		var zr *xz.Reader
 		zr, err = xz.NewReader(rd)
 		r = ioutil.NopCloser(zr)
 	}
 	if err != nil || r == nil {
-		r = ioutil.NopCloser(rd)
+		return uncompressed, ioutil.NopCloser(rd)
 	}
-	return r
+	return cFormat, r
 }
 
 // ReadAll reads all from the reader to the buffer.
 // It returns if beforeSize is accumulated in buffer
 // before the end of read.
 func (m *Document) ReadAll(r io.ReadCloser) error {
-	reader := bufio.NewReader(r)
-	ch := make(chan struct{})
-	go func() {
-		defer close(ch)
-		defer r.Close()
-
-		var line bytes.Buffer
-
-		for {
-			buf, isPrefix, err := reader.ReadLine()
-			if err != nil {
-				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
-					break
-				}
-				log.Printf("error: %v\n", err)
-				m.eof = false
-				return
-			}
-			line.Write(buf)
-			if isPrefix {
-				continue
-			}
-
-			m.mu.Lock()
-			m.lines = append(m.lines, line.String())
-			m.endNum++
-			m.mu.Unlock()
-			if m.endNum == m.beforeSize {
-				ch <- struct{}{}
-			}
-			line.Reset()
-		}
-		m.mu.Lock()
-		m.eof = true
-		m.mu.Unlock()
-	}()
-
-	select {
-	case <-ch:
-		return nil
-	case <-time.After(500 * time.Millisecond):
-		return nil
-	}
+	m.SetSource(NewReaderSource(r))
+	m.waitReady()
+	return nil
 }
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestDocument_ReadAll(t *testing.T) {
@@ -36,3 +39,98 @@ func TestDocument_ReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestDocument_followFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "ov-follow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.WriteString("foo\nba"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.setFollowMode(true)
+	if err := m.ReadFile(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("r\nbaz\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"foo", "bar", "baz"}
+	for i := 0; i < 50; i++ {
+		if m.BufEndNum() == len(want) && m.GetLine(2) == want[2] {
+			break
+		}
+		time.Sleep(followInterval)
+	}
+	for n, w := range want {
+		if got := m.GetLine(n); got != w {
+			t.Errorf("Document.GetLine(%d) = %v, want %v", n, got, w)
+		}
+	}
+}
+
+func TestDocument_followFile_reset(t *testing.T) {
+	tests := []struct {
+		name   string
+		rotate bool
+	}{
+		{name: "truncate", rotate: false},
+		{name: "rotate", rotate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "ov-follow")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			fileName := filepath.Join(dir, "test.log")
+			if err := ioutil.WriteFile(fileName, []byte("old1\nold2\nold3\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.close()
+			m.setFollowMode(true)
+			if err := m.ReadFile(fileName); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.BufEndNum(); got != 3 {
+				t.Fatalf("Document.BufEndNum() = %d, want 3", got)
+			}
+
+			if tt.rotate {
+				if err := os.Rename(fileName, fileName+".1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := ioutil.WriteFile(fileName, []byte("new\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 50; i++ {
+				if m.BufEndNum() == 1 && m.GetLine(0) == "new" {
+					break
+				}
+				time.Sleep(followInterval)
+			}
+			if got := m.BufEndNum(); got != 1 {
+				t.Errorf("Document.BufEndNum() = %d, want 1", got)
+			}
+			if got := m.GetLine(0); got != "new" {
+				t.Errorf("Document.GetLine(0) = %v, want new", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package oviewer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// saveRange is a regular expression for the line range of the save input.
+var saveRange = regexp.MustCompile(`^(\d*)-(\d*)$|^(\d+)$`)
+
+// saveOption is the parsed input of the save mode.
+type saveOption struct {
+	// start and end are the line numbers of the range (1-origin, inclusive).
+	// Zero means the beginning or the end of the document.
+	start int
+	end   int
+	// fileName is the name of the file to write.
+	fileName string
+	// overwrite overwrites the existing file if true.
+	overwrite bool
+}
+
+// parseSaveInput parses the input of the save mode.
+// The input is "[range] [!]filename", for example "10-20 out.txt".
+func parseSaveInput(input string) (saveOption, error) {
+	opt := saveOption{}
+	input = strings.TrimSpace(input)
+	fields := strings.SplitN(input, " ", 2)
+	if len(fields) == 2 {
+		if r := saveRange.FindStringSubmatch(fields[0]); r != nil {
+			if r[3] != "" {
+				r[1], r[2] = r[3], r[3]
+			}
+			opt.start, _ = strconv.Atoi(r[1])
+			opt.end, _ = strconv.Atoi(r[2])
+			if opt.end != 0 && opt.start > opt.end {
+				return opt, fmt.Errorf("%w [%s]", ErrInvalidRange, fields[0])
+			}
+			input = strings.TrimSpace(fields[1])
+		}
+	}
+	if strings.HasPrefix(input, "!") {
+		opt.overwrite = true
+		input = strings.TrimSpace(input[1:])
+	}
+	if input == "" {
+		return opt, ErrMissingFile
+	}
+	opt.fileName = expandHome(input)
+	return opt, nil
+}
+
+// setSaveMode waits for the input of the file name to save.
+func (root *Root) setSaveMode() {
+	input := root.input
+	input.value = ""
+	input.cursorX = 0
+	input.mode = Save
+	input.EventInput = newSaveInput()
+}
+
+// toggleStripEscapeSequence toggles whether to strip escape sequences when saving.
+func (root *Root) toggleStripEscapeSequence() {
+	root.StripEscapeSequence = !root.StripEscapeSequence
+}
+
+// saveDocument writes the current document to a file.
+func (root *Root) saveDocument(input string) {
+	opt, err := parseSaveInput(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+
+	m := root.Doc
+	start := 0
+	end := m.BufEndNum()
+	if opt.start > 0 {
+		start = m.lineIndex(opt.start - 1)
+	}
+	if opt.end > 0 {
+		end = min(m.lineIndex(opt.end-1)+1, end)
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opt.overwrite {
+		flag |= os.O_EXCL
+	}
+	f, err := os.OpenFile(opt.fileName, flag, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			err = fmt.Errorf("%s: %w", opt.fileName, ErrFileExists)
+		}
+		root.setMessage(err.Error())
+		return
+	}
+
+	n, err := m.export(f, start, end, root.StripEscapeSequence)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.setMessage(fmt.Sprintf("Saved %d lines to %s", n, opt.fileName))
+}
+
+// export writes the lines from start to end (exclusive) to w.
+// If strip is true, escape sequences and overstrikes are removed.
+// It returns the number of lines written.
+func (m *Document) export(w io.Writer, start int, end int, strip bool) (int, error) {
+	bw := bufio.NewWriter(w)
+	start = max(start, 0)
+	end = min(end, m.BufEndNum())
+	n := 0
+	for lineNum := start; lineNum < end; lineNum++ {
+		line := m.GetLine(lineNum)
+		if strip && strings.ContainsAny(line, "\x1b\b") {
+			line = stripEscapeSequence.ReplaceAllString(line, "")
+		}
+		if _, err := bw.WriteString(line); err != nil {
+			return n, err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, bw.Flush()
+}
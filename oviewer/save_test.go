@@ -0,0 +1,132 @@
+package oviewer
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseSaveInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    saveOption
+		wantErr error
+	}{
+		{
+			name:  "testFile",
+			input: "out.txt",
+			want:  saveOption{fileName: "out.txt"},
+		},
+		{
+			name:  "testRange",
+			input: "10-20 out.txt",
+			want:  saveOption{start: 10, end: 20, fileName: "out.txt"},
+		},
+		{
+			name:  "testOpenRange",
+			input: "10- out.txt",
+			want:  saveOption{start: 10, fileName: "out.txt"},
+		},
+		{
+			name:  "testOneLine",
+			input: "5 out.txt",
+			want:  saveOption{start: 5, end: 5, fileName: "out.txt"},
+		},
+		{
+			name:  "testOverwrite",
+			input: "1-2 !out.txt",
+			want:  saveOption{start: 1, end: 2, fileName: "out.txt", overwrite: true},
+		},
+		{
+			name:  "testSpace",
+			input: "my out.txt",
+			want:  saveOption{fileName: "my out.txt"},
+		},
+		{
+			name:    "testNoFile",
+			input:   "",
+			wantErr: ErrMissingFile,
+		},
+		{
+			name:    "testInvalidRange",
+			input:   "20-10 out.txt",
+			wantErr: ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSaveInput(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseSaveInput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSaveInput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_export(t *testing.T) {
+	type args struct {
+		start int
+		end   int
+		strip bool
+	}
+	tests := []struct {
+		name string
+		str  string
+		args args
+		want string
+	}{
+		{
+			name: "testAll",
+			str:  "a\nb\nc\n",
+			args: args{start: 0, end: 3},
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "testRange",
+			str:  "a\nb\nc\n",
+			args: args{start: 1, end: 2},
+			want: "b\n",
+		},
+		{
+			name: "testKeepEscape",
+			str:  "\x1b[31mred\x1b[0m\nb\bbold\n",
+			args: args{start: 0, end: 2},
+			want: "\x1b[31mred\x1b[0m\nb\bbold\n",
+		},
+		{
+			name: "testStripEscape",
+			str:  "\x1b[31mred\x1b[0m\nb\bbold\n",
+			args: args{start: 0, end: 2, strip: true},
+			want: "red\nbold\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.ReadAll(ioutil.NopCloser(bytes.NewBufferString(tt.str))); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 50 && !m.BufEOF(); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			var b bytes.Buffer
+			if _, err := m.export(&b, tt.args.start, tt.args.end, tt.args.strip); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Document.export() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
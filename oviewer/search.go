@@ -2,6 +2,7 @@ package oviewer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -26,7 +27,7 @@ func (root *Root) forwardSearch(ctx context.Context, input string) {
 		return
 	}
 	root.input.value = input
-	root.search(ctx, root.Doc.lineNum, root.searchLine)
+	root.search(ctx, root.Doc.lineNum, true)
 }
 
 // backSearch is backward search.
@@ -36,13 +37,20 @@ func (root *Root) backSearch(ctx context.Context, input string) {
 		return
 	}
 	root.input.value = input
-	root.search(ctx, root.Doc.lineNum, root.backSearchLine)
+	root.search(ctx, root.Doc.lineNum, false)
 }
 
 // search searches forward or backward.
-func (root *Root) search(ctx context.Context, num int, searchFunc func(context.Context, int) (int, error)) {
+// If SearchAllDocs is enabled, the search continues
+// to the next (or previous) document.
+func (root *Root) search(ctx context.Context, num int, forward bool) {
 	root.setMessage(fmt.Sprintf("search:%v (%v)Cancel", root.input.value, strings.Join(root.cancelKeys, ",")))
 
+	searchFunc := root.backSearchLine
+	if forward {
+		searchFunc = root.searchLine
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -51,25 +59,68 @@ func (root *Root) search(ctx context.Context, num int, searchFunc func(context.C
 		return root.cancelWait(cancel)
 	})
 
+	docNum := root.CurrentDoc
+	lineNum := 0
 	eg.Go(func() error {
-		lineNum, err := searchFunc(ctx, num)
-		if err != nil {
+		defer root.searchQuit()
+		n, err := searchFunc(ctx, root.Doc, num)
+		if err == nil {
+			lineNum = n
+			return nil
+		}
+		if !errors.Is(err, ErrNotFound) || !root.SearchAllDocs || root.Doc != root.DocList[root.CurrentDoc] {
 			return err
 		}
-		root.moveLine(lineNum - root.Doc.Header)
-		return nil
+		docNum, lineNum, err = root.searchDocs(ctx, searchFunc, forward)
+		return err
 	})
 
 	if err := eg.Wait(); err != nil {
+		if forward && errors.Is(err, ErrNotFound) {
+			root.input.value = ""
+			root.input.reg = nil
+		}
 		root.setMessage(err.Error())
 		return
 	}
+
+	if docNum != root.CurrentDoc {
+		root.CurrentDoc = docNum
+		root.setDocument(root.DocList[docNum])
+		root.moveLine(lineNum - root.Doc.Header)
+		root.setMessage(fmt.Sprintf("search:%v [%s]", root.input.value, root.Doc.FileName))
+		return
+	}
+	root.moveLine(lineNum - root.Doc.Header)
 	root.setMessage(fmt.Sprintf("search:%v", root.input.value))
 }
 
+// searchDocs searches the documents after (or before) the current document.
+// It returns the number of the document and the line that matched.
+func (root *Root) searchDocs(ctx context.Context, searchFunc func(context.Context, *Document, int) (int, error), forward bool) (int, int, error) {
+	step := -1
+	if forward {
+		step = 1
+	}
+	for n := root.CurrentDoc + step; n >= 0 && n < len(root.DocList); n += step {
+		m := root.DocList[n]
+		start := 0
+		if !forward {
+			start = m.BufEndNum() - 1
+		}
+		lineNum, err := searchFunc(ctx, m, start)
+		if err == nil {
+			return n, lineNum, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return 0, 0, err
+		}
+	}
+	return 0, 0, ErrNotFound
+}
+
 // searchLine is searches below from the specified line.
-func (root *Root) searchLine(ctx context.Context, num int) (int, error) {
-	defer root.searchQuit()
+func (root *Root) searchLine(ctx context.Context, m *Document, num int) (int, error) {
 	num = max(num, 0)
 
 	if root.input.value == "" {
@@ -83,8 +134,8 @@ func (root *Root) searchLine(ctx context.Context, num int) (int, error) {
 
 	searchType := getSearchType(root.input.value, root.CaseSensitive)
 
-	for n := num; n < root.Doc.BufEndNum(); n++ {
-		if root.contains(root.Doc.GetLine(n), searchType) {
+	for n := num; n < m.BufEndNum(); n++ {
+		if root.contains(m.GetLine(n), searchType) {
 			return n, nil
 		}
 		select {
@@ -94,16 +145,12 @@ func (root *Root) searchLine(ctx context.Context, num int) (int, error) {
 		}
 	}
 
-	root.input.value = ""
-	root.input.reg = nil
-
 	return 0, ErrNotFound
 }
 
 // backsearch is searches upward from the specified line.
-func (root *Root) backSearchLine(ctx context.Context, num int) (int, error) {
-	defer root.searchQuit()
-	num = min(num, root.Doc.BufEndNum()-1)
+func (root *Root) backSearchLine(ctx context.Context, m *Document, num int) (int, error) {
+	num = min(num, m.BufEndNum()-1)
 
 	root.input.reg = regexpComple(root.input.value, root.CaseSensitive)
 	if root.input.reg == nil {
@@ -113,7 +160,7 @@ func (root *Root) backSearchLine(ctx context.Context, num int) (int, error) {
 	searchType := getSearchType(root.input.value, root.CaseSensitive)
 
 	for n := num; n >= 0; n-- {
-		if root.contains(root.Doc.GetLine(n), searchType) {
+		if root.contains(m.GetLine(n), searchType) {
 			return n, nil
 		}
 		select {
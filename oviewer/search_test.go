@@ -1,6 +1,8 @@
 package oviewer
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"regexp"
 	"testing"
@@ -293,3 +295,91 @@ func Test_searchPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestRoot_searchDocs(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		current  int
+		forward  bool
+		wantDoc  int
+		wantLine int
+		wantErr  error
+	}{
+		{
+			name:     "testForward",
+			word:     "foo",
+			current:  0,
+			forward:  true,
+			wantDoc:  2,
+			wantLine: 1,
+		},
+		{
+			name:     "testBackward",
+			word:     "foo",
+			current:  3,
+			forward:  false,
+			wantDoc:  2,
+			wantLine: 2,
+		},
+		{
+			name:     "testBackwardSkip",
+			word:     "bar",
+			current:  2,
+			forward:  false,
+			wantDoc:  0,
+			wantLine: 0,
+		},
+		{
+			name:    "testNotFound",
+			word:    "baz",
+			current: 0,
+			forward: true,
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "testLastDoc",
+			word:    "foo",
+			current: 3,
+			forward: true,
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := [][]string{
+				{"bar", "a"},
+				{"a", "b"},
+				{"a", "foo", "foo"},
+				{"foo"},
+			}
+			docs := make([]*Document, len(lines))
+			for i, l := range lines {
+				s := NewSliceSource(l)
+				s.SetEOF(true)
+				m, err := NewSourceDocument(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				docs[i] = m
+			}
+			root, err := NewOviewer(docs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root.CurrentDoc = tt.current
+			root.input.value = tt.word
+			searchFunc := root.searchLine
+			if !tt.forward {
+				searchFunc = root.backSearchLine
+			}
+			gotDoc, gotLine, err := root.searchDocs(context.Background(), searchFunc, tt.forward)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Root.searchDocs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotDoc != tt.wantDoc || gotLine != tt.wantLine {
+				t.Errorf("Root.searchDocs() = %d, %d, want %d, %d", gotDoc, gotLine, tt.wantDoc, tt.wantLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package oviewer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// sectionIndex is the lines that match the section delimiter.
+type sectionIndex struct {
+	// delimiter is the section delimiter that reg was compiled from.
+	delimiter string
+	reg       *regexp.Regexp
+	// header is the number of the header lines that are not sections.
+	header int
+	lines  []int
+	// end is the number of the scanned lines.
+	end int
+	// scan scans the lines in the background for the section header.
+	scan backgroundScan
+}
+
+// scanSection scans the lines of the body before lineNum into the index.
+func (m *Document) scanSection(lineNum int) error {
+	if err := m.prepareSection(); err != nil {
+		return err
+	}
+	m.scanSectionLines(lineNum)
+	return nil
+}
+
+// prepareSection rebuilds the index if the section delimiter or the header has changed.
+func (m *Document) prepareSection() error {
+	idx := &m.section
+	if idx.reg != nil && idx.delimiter == m.SectionDelimiter && idx.header == m.Header {
+		return nil
+	}
+	reg, err := regexp.Compile(m.SectionDelimiter)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSectionDelimiter, err)
+	}
+	idx.scan.reset()
+	*idx = sectionIndex{delimiter: m.SectionDelimiter, reg: reg, header: m.Header, scan: idx.scan}
+	return nil
+}
+
+// scanSectionLines scans the lines before lineNum into the prepared index.
+// It does not read the status, so that it can be called in the background.
+func (m *Document) scanSectionLines(lineNum int) {
+	idx := &m.section
+	end := min(lineNum, m.BufEndNum())
+	for n := max(idx.end, idx.header); n < end; n++ {
+		if idx.reg.MatchString(m.plainLine(n)) {
+			idx.lines = append(idx.lines, n)
+		}
+	}
+	idx.end = max(idx.end, end)
+}
+
+// resetSection discards the index after the line n.
+func (m *Document) resetSection(n int) {
+	m.sectionMu.Lock()
+	defer m.sectionMu.Unlock()
+	idx := &m.section
+	if n >= idx.end {
+		return
+	}
+	idx.end = max(n, 0)
+	i := sort.SearchInts(idx.lines, idx.end)
+	idx.lines = idx.lines[:i]
+	idx.scan.reset()
+}
+
+// scanningSection returns true while the lines are scanned in the background,
+// and once after they are scanned.
+func (m *Document) scanningSection() bool {
+	m.sectionMu.Lock()
+	defer m.sectionMu.Unlock()
+	return m.section.scan.busy()
+}
+
+// nextSection returns the nearest section heading after (or before) lineNum.
+func (m *Document) nextSection(lineNum int, forward bool) (int, error) {
+	if m.SectionDelimiter == "" {
+		return 0, ErrNoSectionDelimiter
+	}
+	m.sectionMu.Lock()
+	defer m.sectionMu.Unlock()
+	idx := &m.section
+	if forward {
+		if err := m.scanSection(lineNum + 1); err != nil {
+			return 0, err
+		}
+		// Scan only until the next heading is found.
+		for idx.end < m.BufEndNum() && (len(idx.lines) == 0 || idx.lines[len(idx.lines)-1] <= lineNum) {
+			if err := m.scanSection(idx.end + 1); err != nil {
+				return 0, err
+			}
+		}
+		i := sort.SearchInts(idx.lines, lineNum+1)
+		if i == len(idx.lines) {
+			return 0, ErrNotFound
+		}
+		return idx.lines[i], nil
+	}
+	if err := m.scanSection(lineNum); err != nil {
+		return 0, err
+	}
+	i := sort.SearchInts(idx.lines, lineNum)
+	if i == 0 {
+		return 0, ErrNotFound
+	}
+	return idx.lines[i-1], nil
+}
+
+// sectionHeading returns the section heading of lineNum.
+func (m *Document) sectionHeading(lineNum int) (int, bool) {
+	if m.SectionDelimiter == "" {
+		return 0, false
+	}
+	m.sectionMu.Lock()
+	defer m.sectionMu.Unlock()
+	if err := m.prepareSection(); err != nil {
+		return 0, false
+	}
+	// It is called on every draw, so the lines far ahead are scanned in the background,
+	// and no heading is returned until the index reaches lineNum.
+	if !m.section.scan.start(&m.sectionMu, m.section.end, min(lineNum+1, m.BufEndNum()), m.scanSectionLines, m.done) {
+		return 0, false
+	}
+	if lineNum >= m.section.end {
+		return 0, false
+	}
+	lines := m.section.lines
+	i := sort.SearchInts(lines, lineNum+1)
+	if i == 0 {
+		return 0, false
+	}
+	return lines[i-1], true
+}
+
+// nextSection moves to the next section.
+func (root *Root) nextSection() {
+	root.moveSection(true)
+}
+
+// previousSection moves to the previous section.
+func (root *Root) previousSection() {
+	root.moveSection(false)
+}
+
+func (root *Root) moveSection(forward bool) {
+	m := root.Doc
+	lineNum, err := m.nextSection(m.lineNum+m.Header, forward)
+	if errors.Is(err, ErrNotFound) {
+		root.setMessage(fmt.Sprintf("section %s", err))
+		return
+	}
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.moveLine(lineNum - m.Header)
+	root.setMessage(fmt.Sprintf("Moved to section at line %d", m.lineNumber(lineNum)-m.Header+1))
+}
+
+// sectionHeaderLen returns the number of the rows of the sticky section heading.
+func (root *Root) sectionHeaderLen() int {
+	if root.Doc.SectionHeader && root.Doc.SectionDelimiter != "" {
+		return 1
+	}
+	return 0
+}
+
+// stickySection returns the section heading to pin below the header.
+// It returns false if the heading is on the top line.
+func (root *Root) stickySection() (int, bool) {
+	m := root.Doc
+	if root.sectionHeaderLen() == 0 {
+		return 0, false
+	}
+	top := m.lineNum + m.Header
+	n, ok := m.sectionHeading(top)
+	if !ok || n == top {
+		return 0, false
+	}
+	return n, true
+}
+
+// setSectionDelimiter sets the section delimiter from the input.
+func (root *Root) setSectionDelimiter(input string) {
+	if _, err := regexp.Compile(input); err != nil {
+		root.setMessage(fmt.Sprintf("%s: %s", ErrInvalidSectionDelimiter, err))
+		return
+	}
+	root.Doc.SectionDelimiter = input
+	if input == "" {
+		root.setMessage("Set section delimiter off")
+		return
+	}
+	root.setMessage(fmt.Sprintf("Set section delimiter %s", input))
+}
+
+// toggleSectionHeader toggles SectionHeader each time it is called.
+func (root *Root) toggleSectionHeader() {
+	root.Doc.SectionHeader = !root.Doc.SectionHeader
+	root.setMessage(fmt.Sprintf("Set SectionHeader %t", root.Doc.SectionHeader))
+}
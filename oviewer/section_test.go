@@ -0,0 +1,136 @@
+package oviewer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testSections = []string{
+	"header",
+	"# one",
+	"text",
+	"## two",
+	"text",
+	"text",
+	"# three",
+	"text",
+}
+
+func TestDocument_nextSection(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		lineNum   int
+		forward   bool
+		want      int
+		wantErr   error
+	}{
+		{name: "next", delimiter: "^#", lineNum: 1, forward: true, want: 3},
+		{name: "nextFromText", delimiter: "^#", lineNum: 4, forward: true, want: 6},
+		{name: "noNext", delimiter: "^#", lineNum: 6, forward: true, wantErr: ErrNotFound},
+		{name: "previous", delimiter: "^#", lineNum: 6, forward: false, want: 3},
+		{name: "previousFromText", delimiter: "^#", lineNum: 2, forward: false, want: 1},
+		{name: "noPrevious", delimiter: "^#", lineNum: 1, forward: false, wantErr: ErrNotFound},
+		{name: "level", delimiter: "^# ", lineNum: 1, forward: true, want: 6},
+		{name: "notHeader", delimiter: "^header", lineNum: 0, forward: true, wantErr: ErrNotFound},
+		{name: "noDelimiter", delimiter: "", lineNum: 1, forward: true, wantErr: ErrNoSectionDelimiter},
+		{name: "invalid", delimiter: "(", lineNum: 1, forward: true, wantErr: ErrInvalidSectionDelimiter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource(testSections))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.Header = 1
+			m.SectionDelimiter = tt.delimiter
+			got, err := m.nextSection(tt.lineNum, tt.forward)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Document.nextSection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Document.nextSection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_sectionHeading(t *testing.T) {
+	tests := []struct {
+		name    string
+		lineNum int
+		want    int
+		wantOK  bool
+	}{
+		{name: "header", lineNum: 0, wantOK: false},
+		{name: "heading", lineNum: 1, want: 1, wantOK: true},
+		{name: "text", lineNum: 5, want: 3, wantOK: true},
+		{name: "last", lineNum: 7, want: 6, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSourceDocument(NewSliceSource(testSections))
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.Header = 1
+			m.SectionDelimiter = "^#"
+			got, ok := m.sectionHeading(tt.lineNum)
+			if ok != tt.wantOK {
+				t.Errorf("Document.sectionHeading() ok = %v, want %v", ok, tt.wantOK)
+				return
+			}
+			if ok && got != tt.want {
+				t.Errorf("Document.sectionHeading() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_resetSection(t *testing.T) {
+	m, err := NewSourceDocument(NewSliceSource(testSections))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SectionDelimiter = "^#"
+	if got, ok := m.sectionHeading(7); !ok || got != 6 {
+		t.Fatalf("Document.sectionHeading() = %v, %v, want 6, true", got, ok)
+	}
+	m.resetSection(4)
+	if m.section.end != 4 || len(m.section.lines) != 2 {
+		t.Errorf("Document.resetSection() end = %v, lines = %v", m.section.end, m.section.lines)
+	}
+	if got, ok := m.sectionHeading(7); !ok || got != 6 {
+		t.Errorf("Document.sectionHeading() = %v, %v, want 6, true", got, ok)
+	}
+}
+
+func TestDocument_sectionHeading_background(t *testing.T) {
+	lines := []string{"# first"}
+	for len(lines) < checkpointLines*3 {
+		lines = append(lines, "text")
+	}
+	lines = append(lines, "# last", "text")
+	m, err := NewSourceDocument(NewSliceSource(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SectionDelimiter = "^#"
+	far := len(lines) - 1
+	// The lines far ahead are scanned in the background.
+	if _, ok := m.sectionHeading(far); ok {
+		t.Errorf("Document.sectionHeading(%d) ok = true before the scan, want false", far)
+	}
+	for i := 0; i < 100 && m.scanningStates(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got, ok := m.sectionHeading(far); !ok || got != far-1 {
+		t.Errorf("Document.sectionHeading(%d) = %v, %v, want %v, true", far, got, ok, far-1)
+	}
+	// The lines near the scanned ones are scanned at once.
+	if got, ok := m.sectionHeading(10); !ok || got != 0 {
+		t.Errorf("Document.sectionHeading(10) = %v, %v, want 0, true", got, ok)
+	}
+}
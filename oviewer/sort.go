@@ -0,0 +1,214 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// sortOption is the option of sorting by a column.
+type sortOption struct {
+	// numeric compares the columns as numbers.
+	// The columns that are not numbers are placed after the numbers.
+	numeric bool
+	// reverse sorts in descending order.
+	reverse bool
+}
+
+// parseSortOption parses the input of the sort mode.
+// "n" is numeric and "r" is reverse, and the empty input is lexical ascending.
+func parseSortOption(input string) (sortOption, error) {
+	var opt sortOption
+	for _, r := range input {
+		switch r {
+		case 'n':
+			opt.numeric = true
+		case 'r':
+			opt.reverse = true
+		case ' ':
+		default:
+			return opt, ErrInvalidSortOption
+		}
+	}
+	return opt, nil
+}
+
+// String returns the option in the input format.
+func (opt sortOption) String() string {
+	str := ""
+	if opt.numeric {
+		str += "n"
+	}
+	if opt.reverse {
+		str += "r"
+	}
+	return str
+}
+
+// sortColumn creates a document sorted by the current column and switches to it.
+// The sort can be canceled by the cancel keys.
+func (root *Root) sortColumn(ctx context.Context, input string) {
+	if !root.Doc.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	opt, err := parseSortOption(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+
+	src := root.DocList[root.CurrentDoc]
+	column := root.Doc.columnNum
+	name := strings.TrimSpace(fmt.Sprintf("sort %d %s", column+1, opt))
+	root.setMessage(fmt.Sprintf("%s (%v)Cancel", name, strings.Join(root.cancelKeys, ",")))
+
+	eg, ctx := errgroup.WithContext(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	eg.Go(func() error {
+		return root.cancelWait(cancel)
+	})
+
+	var m *Document
+	eg.Go(func() error {
+		defer root.searchQuit()
+		var err error
+		m, err = NewSortDocument(ctx, src, column, opt)
+		return err
+	})
+
+	if err := eg.Wait(); err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+
+	m.FileName = fmt.Sprintf("%s [%s]", src.FileName, name)
+	m.ColumnMode = true
+	m.columnNum = column
+	root.insertDocument(m)
+	root.Doc.x = root.columnModeX()
+	if !src.BufEOF() {
+		name += fmt.Sprintf(" (%d lines read)", src.BufEndNum())
+	}
+	root.setMessage(name)
+}
+
+// sortKey is a line to be sorted.
+type sortKey struct {
+	num    int
+	line   string
+	str    string
+	number float64
+	isNum  bool
+}
+
+// NewSortDocument returns a document that contains the lines of src
+// sorted by the column.
+// The header lines of src are left in place.
+// The lines read so far are sorted.
+// It returns ErrCancel if ctx is canceled.
+func NewSortDocument(ctx context.Context, src *Document, column int, opt sortOption) (*Document, error) {
+	end := src.BufEndNum()
+	header := min(src.Header, end)
+	keys := make([]sortKey, 0, end-header)
+	for n := header; n < end; n++ {
+		keys = append(keys, src.sortKey(n, column, opt.numeric))
+		select {
+		case <-ctx.Done():
+			return nil, ErrCancel
+		default:
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].less(keys[j], opt)
+	})
+	if ctx.Err() != nil {
+		return nil, ErrCancel
+	}
+
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.inherit(src)
+	lines := make([]string, 0, end)
+	m.lineMap = make([]int, 0, end)
+	for n := 0; n < header; n++ {
+		lines = append(lines, src.GetLine(n))
+		m.lineMap = append(m.lineMap, src.lineNumber(n))
+	}
+	for _, k := range keys {
+		lines = append(lines, k.line)
+		m.lineMap = append(m.lineMap, src.lineNumber(k.num))
+	}
+	m.setLineOrder()
+
+	s := NewSliceSource(lines)
+	s.SetEOF(true)
+	m.SetSource(s)
+	return m, nil
+}
+
+// sortKey returns the key of the line to sort by the column.
+// The line is read only once.
+func (m *Document) sortKey(lineNum int, column int, numeric bool) sortKey {
+	k := sortKey{num: lineNum, line: m.GetLine(lineNum)}
+	line := m.toViewLine(k.line)
+	if strings.ContainsAny(line, "\x1b\b") {
+		line = stripEscapeSequence.ReplaceAllString(line, "")
+	}
+	start, end := m.columnRange(lineNum, line, column)
+	if start < 0 || end < 0 {
+		return k
+	}
+	k.str = line[start:end]
+	if numeric {
+		str := strings.TrimSpace(k.str)
+		if m.ColumnCSV {
+			str = strings.Trim(str, m.csvParser().quote)
+		}
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			k.number = f
+			k.isNum = true
+		}
+	}
+	return k
+}
+
+// less compares the keys.
+// The keys that are not numbers are placed last even in reverse order.
+func (k sortKey) less(o sortKey, opt sortOption) bool {
+	if opt.numeric && k.isNum != o.isNum {
+		return k.isNum
+	}
+	if opt.reverse {
+		k, o = o, k
+	}
+	if opt.numeric && k.isNum {
+		return k.number < o.number
+	}
+	return k.str < o.str
+}
+
+// setLineOrder sets lineOrder if lineMap is not in ascending order,
+// so that lineIndex can search it.
+func (m *Document) setLineOrder() {
+	if sort.IntsAreSorted(m.lineMap) {
+		m.lineOrder = nil
+		return
+	}
+	order := make([]int, len(m.lineMap))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return m.lineMap[order[i]] < m.lineMap[order[j]]
+	})
+	m.lineOrder = order
+}
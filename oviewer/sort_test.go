@@ -0,0 +1,124 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_parseSortOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    sortOption
+		wantErr bool
+	}{
+		{name: "lexical", input: "", want: sortOption{}},
+		{name: "numeric", input: "n", want: sortOption{numeric: true}},
+		{name: "reverse", input: "r", want: sortOption{reverse: true}},
+		{name: "both", input: "n r", want: sortOption{numeric: true, reverse: true}},
+		{name: "invalid", input: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSortOption(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSortOption() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseSortOption() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSortDocument(t *testing.T) {
+	lines := []string{
+		"name,size",
+		"b,10",
+		"c,9",
+		"a,-",
+		"d,100",
+	}
+	tests := []struct {
+		name      string
+		column    int
+		opt       sortOption
+		wantLines []string
+		wantMap   []int
+	}{
+		{
+			name:      "lexical",
+			column:    0,
+			wantLines: []string{"name,size", "a,-", "b,10", "c,9", "d,100"},
+			wantMap:   []int{0, 3, 1, 2, 4},
+		},
+		{
+			name:      "lexicalReverse",
+			column:    0,
+			opt:       sortOption{reverse: true},
+			wantLines: []string{"name,size", "d,100", "c,9", "b,10", "a,-"},
+			wantMap:   []int{0, 4, 2, 1, 3},
+		},
+		{
+			name:      "numeric",
+			column:    1,
+			opt:       sortOption{numeric: true},
+			wantLines: []string{"name,size", "c,9", "b,10", "d,100", "a,-"},
+			wantMap:   []int{0, 2, 1, 4, 3},
+		},
+		{
+			name:      "numericReverse",
+			column:    1,
+			opt:       sortOption{numeric: true, reverse: true},
+			wantLines: []string{"name,size", "d,100", "b,10", "c,9", "a,-"},
+			wantMap:   []int{0, 4, 1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := NewSourceDocument(NewSliceSource(lines))
+			if err != nil {
+				t.Fatal(err)
+			}
+			src.ColumnDelimiter = ","
+			src.Header = 1
+			m, err := NewSortDocument(context.Background(), src, tt.column, tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for n := 0; n < m.BufEndNum(); n++ {
+				got = append(got, m.GetLine(n))
+			}
+			if !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("NewSortDocument() lines = %v, want %v", got, tt.wantLines)
+			}
+			if !reflect.DeepEqual(m.lineMap, tt.wantMap) {
+				t.Errorf("NewSortDocument() lineMap = %v, want %v", m.lineMap, tt.wantMap)
+			}
+			for n, num := range tt.wantMap {
+				if got := m.lineIndex(num); got != n {
+					t.Errorf("Document.lineIndex(%d) = %v, want %v", num, got, n)
+				}
+			}
+			if got := m.lastLineNumber(); got != 4 {
+				t.Errorf("Document.lastLineNumber() = %v, want %v", got, 4)
+			}
+		})
+	}
+}
+
+func TestNewSortDocument_cancel(t *testing.T) {
+	src, err := NewSourceDocument(NewSliceSource([]string{"b", "a"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewSortDocument(ctx, src, 0, sortOption{}); !errors.Is(err, ErrCancel) {
+		t.Errorf("NewSortDocument() error = %v, want %v", err, ErrCancel)
+	}
+}
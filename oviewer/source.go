@@ -0,0 +1,343 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LineSource is the interface that provides the lines of a Document.
+// A Document reads the lines only through this interface,
+// so anything that can be represented by lines can be displayed.
+type LineSource interface {
+	// Line returns the line n (0-origin).
+	// It returns an empty string if n is out of range.
+	Line(n int) string
+	// Len returns the number of lines available now.
+	// It can increase while the source is being read.
+	Len() int
+	// EOF returns true if no more lines are added.
+	EOF() bool
+	// SetNotify sets the function that is called when lines that
+	// may have already been read by Line are modified.
+	// n is the first modified line, the lines after it are considered
+	// modified too. Appending lines does not need to be notified.
+	SetNotify(f func(n int))
+}
+
+// OffsetSource is a LineSource that knows the byte offset of each line.
+// It is used to move to a byte offset and to show the byte position.
+type OffsetSource interface {
+	LineSource
+	// Offset returns the byte offset of the beginning of the line n.
+	// If n is not read yet, it returns Size.
+	Offset(n int) int64
+	// LineAt returns the line that contains the byte offset.
+	LineAt(offset int64) int
+	// Size returns the number of bytes read.
+	Size() int64
+}
+
+// beforeSize is the number of lines to read before the document is displayed.
+const beforeSize = 1000
+
+// readySource is a LineSource that reads lines in the background.
+type readySource interface {
+	// ready returns a channel that is closed when the first
+	// beforeSize lines have been read or EOF is reached.
+	ready() <-chan struct{}
+}
+
+// partialSource is a LineSource whose last line can be joined
+// with the text read after it.
+type partialSource interface {
+	// partialLine returns true if the last line does not end with a newline.
+	partialLine() bool
+}
+
+// lineAppender is a LineSource to which readLines appends lines.
+type lineAppender interface {
+	// appendLine appends a line.
+	// If the last line was incomplete, buf is joined to it.
+	appendLine(buf []byte, complete bool)
+	Len() int
+}
+
+// SliceSource is a LineSource that holds the lines in a slice of strings.
+type SliceSource struct {
+	lines  []string
+	eof    bool
+	notify func(n int)
+
+	mu sync.Mutex
+}
+
+// NewSliceSource returns a SliceSource that contains lines.
+// EOF is false until SetEOF is called.
+func NewSliceSource(lines []string) *SliceSource {
+	return &SliceSource{lines: lines}
+}
+
+// Line returns the line n.
+func (s *SliceSource) Line(n int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 || n >= len(s.lines) {
+		return ""
+	}
+	return s.lines[n]
+}
+
+// Len returns the number of lines.
+func (s *SliceSource) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.lines)
+}
+
+// EOF returns true if no more lines are added.
+func (s *SliceSource) EOF() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eof
+}
+
+// SetNotify sets the function that is called when lines are modified.
+func (s *SliceSource) SetNotify(f func(n int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = f
+}
+
+// SetEOF sets whether more lines are added.
+func (s *SliceSource) SetEOF(eof bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eof = eof
+}
+
+// Append appends lines.
+func (s *SliceSource) Append(lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines = append(s.lines, lines...)
+}
+
+// Reset removes all lines and notifies the modification.
+func (s *SliceSource) Reset() {
+	s.mu.Lock()
+	s.lines = s.lines[:0]
+	s.mu.Unlock()
+	s.changed(0)
+}
+
+// Set replaces the line n and notifies the modification.
+func (s *SliceSource) Set(n int, line string) {
+	s.mu.Lock()
+	if n < 0 || n >= len(s.lines) {
+		s.mu.Unlock()
+		return
+	}
+	s.lines[n] = line
+	s.mu.Unlock()
+	s.changed(n)
+}
+
+// Write appends p as lines.
+// It matches the interface of io.Writer.
+func (s *SliceSource) Write(p []byte) (int, error) {
+	str := strings.TrimSuffix(string(p), "\n")
+	s.Append(strings.Split(str, "\n")...)
+	return len(p), nil
+}
+
+// changed calls the notify function if it is set.
+func (s *SliceSource) changed(n int) {
+	s.mu.Lock()
+	notify := s.notify
+	s.mu.Unlock()
+	if notify != nil {
+		notify(n)
+	}
+}
+
+// ReaderSource is a LineSource that reads lines from io.Reader into memory.
+// It is used for stdin, compressed files and the output of commands.
+type ReaderSource struct {
+	SliceSource
+	// partial is true if the last line does not end with a newline.
+	partial bool
+	// offsets is the byte offset of the beginning of each line.
+	offsets []int64
+	// size is the number of bytes read.
+	size int64
+	// r is the reader being read.
+	r io.ReadCloser
+
+	readyCh   chan struct{}
+	readyOnce sync.Once
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReaderSource returns a ReaderSource that reads r in the background.
+// r is closed when EOF is reached.
+func NewReaderSource(r io.ReadCloser) *ReaderSource {
+	s := &ReaderSource{
+		SliceSource: SliceSource{lines: make([]string, 0, beforeSize)},
+		r:           r,
+		readyCh:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go func() {
+		err := readLines(bufio.NewReader(r), s, s.done, s.setReady)
+		r.Close()
+		if s.closed() {
+			err = nil
+		}
+		if err != nil {
+			log.Printf("error: %v\n", err)
+		}
+		s.SetEOF(err == nil)
+		s.setReady()
+	}()
+	return s
+}
+
+// appendLine appends a line.
+func (s *ReaderSource) appendLine(buf []byte, complete bool) {
+	size := int64(len(buf))
+	if complete {
+		buf = bytes.TrimSuffix(buf, []byte("\n"))
+		buf = bytes.TrimSuffix(buf, []byte("\r"))
+	}
+	str := string(buf)
+
+	s.mu.Lock()
+	s.size += size
+	if s.partial {
+		last := len(s.lines) - 1
+		s.lines[last] += str
+		s.partial = !complete
+		s.mu.Unlock()
+		s.changed(last)
+		return
+	}
+	s.lines = append(s.lines, str)
+	s.offsets = append(s.offsets, s.size-size)
+	s.partial = !complete
+	s.mu.Unlock()
+}
+
+// partialLine returns true if the last line does not end with a newline.
+func (s *ReaderSource) partialLine() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.partial
+}
+
+// Offset returns the byte offset of the beginning of the line n.
+func (s *ReaderSource) Offset(n int) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n >= len(s.offsets) {
+		return s.size
+	}
+	return s.offsets[max(n, 0)]
+}
+
+// LineAt returns the line that contains the byte offset.
+func (s *ReaderSource) LineAt(offset int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := sort.Search(len(s.offsets), func(i int) bool {
+		return s.offsets[i] > offset
+	}) - 1
+	return max(n, 0)
+}
+
+// Size returns the number of bytes read.
+func (s *ReaderSource) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+func (s *ReaderSource) ready() <-chan struct{} {
+	return s.readyCh
+}
+
+func (s *ReaderSource) setReady() {
+	s.readyOnce.Do(func() {
+		close(s.readyCh)
+	})
+}
+
+// pipeCloser is a reader that closes with the error returned to the writer,
+// such as *io.PipeReader.
+type pipeCloser interface {
+	CloseWithError(err error) error
+}
+
+// Close stops reading.
+// The reader is closed to unblock the read in progress,
+// and a pipe returns io.ErrClosedPipe to the writer.
+func (s *ReaderSource) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		if p, ok := s.r.(pipeCloser); ok {
+			err = p.CloseWithError(io.ErrClosedPipe)
+			return
+		}
+		err = s.r.Close()
+	})
+	return err
+}
+
+// closed returns true if Close has been called.
+func (s *ReaderSource) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// readLines reads lines from the reader until EOF or done is closed.
+// ready is called when beforeSize lines have been read.
+func readLines(reader *bufio.Reader, a lineAppender, done <-chan struct{}, ready func()) error {
+	var line bytes.Buffer
+	for {
+		buf, err := reader.ReadSlice('\n')
+		line.Write(buf)
+		if err != nil {
+			if errors.Is(err, bufio.ErrBufferFull) {
+				continue
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+				if line.Len() > 0 {
+					a.appendLine(line.Bytes(), false)
+				}
+				return nil
+			}
+			return err
+		}
+		a.appendLine(line.Bytes(), true)
+		line.Reset()
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		if ready != nil && a.Len() == beforeSize {
+			ready()
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package oviewer
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSliceSource_Write(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "testLine",
+			args: []string{"foo\n"},
+			want: []string{"foo"},
+		},
+		{
+			name: "testLines",
+			args: []string{"foo\nbar\n", "baz"},
+			want: []string{"foo", "bar", "baz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSliceSource(nil)
+			for _, a := range tt.args {
+				if _, err := s.Write([]byte(a)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(s.lines, tt.want) {
+				t.Errorf("SliceSource.Write() = %v, want %v", s.lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSourceDocument(t *testing.T) {
+	src := NewSliceSource([]string{"foo", "bar"})
+	src.SetEOF(true)
+	m, err := NewSourceDocument(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BufEndNum() != 2 || !m.BufEOF() {
+		t.Fatalf("BufEndNum() = %d, BufEOF() = %v, want 2, true", m.BufEndNum(), m.BufEOF())
+	}
+	if _, err := m.lineToContents(1, 8); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the cache to be set.
+	time.Sleep(10 * time.Millisecond)
+	// The modification discards the cached contents.
+	src.Set(1, "baz")
+	lc, err := m.lineToContents(1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := contentsToStr(lc); got != "baz" {
+		t.Errorf("lineToContents() = %v, want %v", got, "baz")
+	}
+}
+
+func TestReaderSource_Offset(t *testing.T) {
+	src := NewReaderSource(ioutil.NopCloser(strings.NewReader("foo\r\nbar\n\nbaz")))
+	<-src.ready()
+	tests := []struct {
+		n      int
+		offset int64
+	}{
+		{n: 0, offset: 0},
+		{n: 1, offset: 5},
+		{n: 2, offset: 9},
+		{n: 3, offset: 10},
+	}
+	for _, tt := range tests {
+		if got := src.Offset(tt.n); got != tt.offset {
+			t.Errorf("ReaderSource.Offset(%d) = %d, want %d", tt.n, got, tt.offset)
+		}
+		if got := src.LineAt(tt.offset); got != tt.n {
+			t.Errorf("ReaderSource.LineAt(%d) = %d, want %d", tt.offset, got, tt.n)
+		}
+	}
+	if got := src.LineAt(7); got != 1 {
+		t.Errorf("ReaderSource.LineAt(7) = %d, want 1", got)
+	}
+	if got := src.Size(); got != 13 {
+		t.Errorf("ReaderSource.Size() = %d, want 13", got)
+	}
+}
+
+func TestReaderSource_Close(t *testing.T) {
+	r, w := io.Pipe()
+	src := NewReaderSource(r)
+	if _, err := w.Write([]byte("foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The reader goroutine is blocked because the pipe is quiet.
+	if err := src.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-src.ready():
+	case <-time.After(time.Second):
+		t.Fatal("ReaderSource.Close() did not stop reading")
+	}
+	if !src.EOF() {
+		t.Error("ReaderSource.EOF() = false, want true")
+	}
+	if _, err := w.Write([]byte("bar\n")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
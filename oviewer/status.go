@@ -0,0 +1,176 @@
+package oviewer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// DefaultStatusLeft is the default template of the left side of the status line.
+	DefaultStatusLeft = "{file}:{message}"
+	// DefaultStatusRight is the default template of the right side of the status line.
+	DefaultStatusRight = "{process} {diff} ({line}/{total}{more}) {percent}% {byte}"
+)
+
+// StatusStyle represents the style of the status line.
+type StatusStyle struct {
+	// Foreground is the foreground color name.
+	Foreground string
+	// Background is the background color name.
+	Background string
+	// Bold is bold if true.
+	Bold bool
+	// Reverse reverses the foreground and background colors if true.
+	Reverse bool
+}
+
+// style returns the tcell style.
+func (s StatusStyle) style() tcell.Style {
+	style := tcell.StyleDefault
+	if s.Foreground != "" {
+		style = style.Foreground(tcell.GetColor(s.Foreground))
+	}
+	if s.Background != "" {
+		style = style.Background(tcell.GetColor(s.Background))
+	}
+	return style.Bold(s.Bold).Reverse(s.Reverse)
+}
+
+// statusVariable is a variable of the status line template.
+var statusVariable = regexp.MustCompile(`{[a-z]+}`)
+
+// expandStatus replaces the variables of the template with the values.
+// Unknown variables are left as they are.
+// The spaces around an empty value are collapsed into one,
+// and the spaces in the values and the other parts of the template are kept.
+func expandStatus(format string, values map[string]string) string {
+	var buf []byte
+	// space is the number of the spaces of the template at the end of buf.
+	space := 0
+	// empty is true after an empty value until the next text,
+	// and sep is true if the spaces around it were removed.
+	empty, sep := false, false
+	write := func(str string, template bool) {
+		if empty {
+			if template {
+				trimmed := strings.TrimLeft(str, " ")
+				sep = sep || len(trimmed) < len(str)
+				str = trimmed
+			}
+			if str == "" {
+				return
+			}
+			if sep && len(buf) > 0 {
+				buf = append(buf, ' ')
+			}
+			empty, sep = false, false
+		}
+		buf = append(buf, str...)
+		space = 0
+		if template {
+			space = len(str) - len(strings.TrimRight(str, " "))
+		}
+	}
+
+	last := 0
+	for _, loc := range statusVariable.FindAllStringIndex(format, -1) {
+		write(format[last:loc[0]], true)
+		last = loc[1]
+		v := format[loc[0]:loc[1]]
+		value, ok := values[v[1:len(v)-1]]
+		if !ok {
+			write(v, true)
+			continue
+		}
+		if value != "" {
+			write(value, false)
+			continue
+		}
+		if !empty {
+			empty, sep = true, space > 0
+			buf = buf[:len(buf)-space]
+			space = 0
+		}
+	}
+	write(format[last:], true)
+	return string(buf)
+}
+
+// statusValues returns the values of the status line variables.
+//
+//	{file}    file name
+//	{message} message
+//	{line}    current line number
+//	{total}   number of lines read
+//	{more}    "..." while reading
+//	{percent} position as a percentage
+//	{byte}    byte offset of the current line ("b123")
+//	{col}     selected column in column mode
+//	{diff}    current file and hunk of a diff
+//	{mode}    display modes (wrap/nowrap, column, csv, align, json, syntax, follow)
+//	{search}  search pattern
+//	{doc}     document number
+//	{docs}    number of documents
+//	{process} exit status of the command
+func (root *Root) statusValues() map[string]string {
+	m := root.Doc
+	values := map[string]string{
+		"file":    m.FileName,
+		"message": root.message,
+		"line":    strconv.Itoa(m.lineNum),
+		"total":   strconv.Itoa(m.BufEndNum()),
+		"percent": strconv.Itoa(m.percent(root.bottomPos)),
+		"doc":     strconv.Itoa(root.CurrentDoc + 1),
+		"docs":    strconv.Itoa(len(root.DocList)),
+		"more":    "",
+		"byte":    "",
+		"col":     "",
+		"diff":    m.diffStatus(),
+		"search":  "",
+		"process": "",
+	}
+	if !m.BufEOF() {
+		values["more"] = "..."
+	}
+	if offset, ok := m.lineOffset(m.lineNum + m.Header); ok {
+		values["byte"] = "b" + strconv.FormatInt(offset, 10)
+	}
+	if m.ColumnMode {
+		values["col"] = strconv.Itoa(m.columnNum + 1)
+	}
+
+	modes := []string{"nowrap"}
+	if m.WrapMode {
+		modes[0] = "wrap"
+	}
+	if m.ColumnMode {
+		modes = append(modes, "column")
+		if m.ColumnCSV {
+			modes = append(modes, "csv")
+		}
+	}
+	if m.columnAligned() {
+		modes = append(modes, "align")
+	}
+	if m.JSONMode != "" {
+		modes = append(modes, "json")
+	}
+	if m.lexer() != nil {
+		modes = append(modes, "syntax")
+	}
+	if m.FollowMode {
+		modes = append(modes, "follow")
+	}
+	values["mode"] = strings.Join(modes, ",")
+
+	if reg := root.input.reg; reg != nil {
+		values["search"] = strings.TrimPrefix(reg.String(), "(?i)")
+	}
+	if root.process != nil {
+		values["process"] = root.process.status()
+	}
+	return values
+}
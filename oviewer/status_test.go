@@ -0,0 +1,89 @@
+package oviewer
+
+import "testing"
+
+func Test_expandStatus(t *testing.T) {
+	values := map[string]string{
+		"file":    "test.txt",
+		"line":    "10",
+		"total":   "100",
+		"percent": "10",
+		"more":    "",
+		"byte":    "",
+		"message": "a  b",
+	}
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "testDefault",
+			format: "{file} ({line}/{total}{more}) {percent}%",
+			want:   "test.txt (10/100) 10%",
+		},
+		{
+			name:   "testEmptyValue",
+			format: "{file} {byte} {line}",
+			want:   "test.txt 10",
+		},
+		{
+			name:   "testUnknown",
+			format: "{unknown} {file}",
+			want:   "{unknown} test.txt",
+		},
+		{
+			name:   "testCollapse",
+			format: "{more} {file}  {more}  {line}",
+			want:   "test.txt 10",
+		},
+		{
+			name:   "testSpacesInValue",
+			format: "{file}:{message} {more}",
+			want:   "test.txt:a  b",
+		},
+		{
+			name:   "testSpacesInTemplate",
+			format: "{file}  {line} {more}",
+			want:   "test.txt  10",
+		},
+		{
+			name:   "testNoSpaces",
+			format: "({line}{more}{byte})",
+			want:   "(10)",
+		},
+		{
+			name:   "testNoVariable",
+			format: "ov",
+			want:   "ov",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandStatus(tt.format, values); got != tt.want {
+				t.Errorf("expandStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_statusValues_search(t *testing.T) {
+	m, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewOviewer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.input.reg = regexpComple("foo", false)
+	// The filter input also adds to the candidates of the search.
+	root.input.SearchCandidate.list = append(root.input.SearchCandidate.list, "bar")
+	if got := root.statusValues()["search"]; got != "foo" {
+		t.Errorf("statusValues()[search] = %q, want %q", got, "foo")
+	}
+	root.input.reg = nil
+	if got := root.statusValues()["search"]; got != "" {
+		t.Errorf("statusValues()[search] = %q, want empty", got)
+	}
+}
@@ -0,0 +1,492 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+// chunkLines is the number of lines in a chunk of the file store.
+const chunkLines = 1000
+
+// chunkCacheSize is the maximum number of bytes of the chunks kept in memory.
+const chunkCacheSize = 32 << 20
+
+// fileStore reads the lines of a seekable file on demand.
+// Instead of the lines, it keeps the offset of every chunkLines lines,
+// and reads the chunk containing the line with ReadAt.
+type fileStore struct {
+	file *os.File
+	// offsets is the offset of the first line of each chunk.
+	offsets []int64
+	// cache is a cache of the complete chunks.
+	cache *ristretto.Cache
+
+	// chunkNum, chunkEnd and chunk are the most recently read chunk.
+	// It is used until the end of the chunk changes.
+	chunkNum int
+	chunkEnd int64
+	chunk    *chunk
+}
+
+// chunk is the lines of a chunk.
+type chunk struct {
+	lines []string
+	// starts is the offset of each line from the beginning of the chunk.
+	starts []int64
+}
+
+// newFileStore returns a fileStore that reads the file.
+func newFileStore(fileName string) (*fileStore, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 100000,
+		MaxCost:     chunkCacheSize,
+		BufferItems: 64,
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileStore{
+		file:     f,
+		cache:    cache,
+		chunkNum: -1,
+	}, nil
+}
+
+// addLine records the offset of the line.
+func (s *fileStore) addLine(lineNum int, offset int64) {
+	if lineNum%chunkLines == 0 {
+		s.offsets = append(s.offsets, offset)
+	}
+}
+
+// line returns the line.
+// end is the offset up to which the file has been read.
+func (s *fileStore) line(lineNum int, end int64) (string, error) {
+	c, err := s.loadChunk(lineNum/chunkLines, end)
+	if err != nil {
+		return "", err
+	}
+	i := lineNum % chunkLines
+	if i >= len(c.lines) {
+		return "", ErrOutOfRange
+	}
+	return c.lines[i], nil
+}
+
+// lineOffset returns the offset of the beginning of the line.
+func (s *fileStore) lineOffset(lineNum int, end int64) (int64, error) {
+	n := lineNum / chunkLines
+	c, err := s.loadChunk(n, end)
+	if err != nil {
+		return 0, err
+	}
+	i := lineNum % chunkLines
+	if i >= len(c.starts) {
+		return 0, ErrOutOfRange
+	}
+	return s.offsets[n] + c.starts[i], nil
+}
+
+// offsetLine returns the line that contains the offset.
+func (s *fileStore) offsetLine(offset int64, end int64) (int, error) {
+	n := sort.Search(len(s.offsets), func(i int) bool {
+		return s.offsets[i] > offset
+	}) - 1
+	if n < 0 {
+		return 0, ErrOutOfRange
+	}
+	c, err := s.loadChunk(n, end)
+	if err != nil {
+		return 0, err
+	}
+	offset -= s.offsets[n]
+	i := sort.Search(len(c.starts), func(i int) bool {
+		return c.starts[i] > offset
+	}) - 1
+	return n*chunkLines + max(i, 0), nil
+}
+
+// loadChunk returns the chunk n.
+// The most recently read chunk is reused.
+func (s *fileStore) loadChunk(n int, end int64) (*chunk, error) {
+	if n >= len(s.offsets) {
+		return nil, ErrOutOfRange
+	}
+	if n+1 < len(s.offsets) {
+		end = s.offsets[n+1]
+	}
+
+	if s.chunkNum != n || s.chunkEnd != end {
+		c, err := s.getChunk(n, end)
+		if err != nil {
+			return nil, err
+		}
+		s.chunkNum = n
+		s.chunkEnd = end
+		s.chunk = c
+	}
+	return s.chunk, nil
+}
+
+// getChunk returns the chunk from the cache or the file.
+// Only the complete chunks are cached,
+// because the last chunk grows while reading.
+func (s *fileStore) getChunk(n int, end int64) (*chunk, error) {
+	complete := n+1 < len(s.offsets)
+	if complete {
+		if value, found := s.cache.Get(n); found {
+			if c, ok := value.(*chunk); ok {
+				return c, nil
+			}
+		}
+	}
+
+	start := s.offsets[n]
+	buf := make([]byte, end-start)
+	l, err := s.file.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:l]
+	c := splitLines(buf)
+	if complete {
+		// The cost includes the string headers and the offsets.
+		s.cache.Set(n, c, int64(len(buf)+len(c.lines)*24))
+	}
+	return c, nil
+}
+
+// splitLines splits buf into lines in the same way as appendLine.
+func splitLines(buf []byte) *chunk {
+	c := &chunk{
+		lines:  make([]string, 0, chunkLines),
+		starts: make([]int64, 0, chunkLines),
+	}
+	start := 0
+	for start < len(buf) {
+		c.starts = append(c.starts, int64(start))
+		i := bytes.IndexByte(buf[start:], '\n')
+		if i < 0 {
+			c.lines = append(c.lines, string(buf[start:]))
+			break
+		}
+		line := bytes.TrimSuffix(buf[start:start+i], []byte("\r"))
+		c.lines = append(c.lines, string(line))
+		start += i + 1
+	}
+	return c
+}
+
+// reset discards the offsets and reopens the file.
+// It is used when the file is truncated or rotated.
+func (s *fileStore) reset(fileName string) error {
+	s.offsets = nil
+	s.chunkNum = -1
+	s.chunk = nil
+	s.cache.Clear()
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	s.file.Close()
+	s.file = f
+	return nil
+}
+
+// close closes the file and frees the cache.
+func (s *fileStore) close() {
+	s.file.Close()
+	s.cache.Close()
+	s.chunk = nil
+}
+
+// FileSource is a LineSource that reads a seekable file on demand.
+// The file must be an uncompressed regular file.
+// Only the offsets of the lines are kept in memory.
+type FileSource struct {
+	fileName string
+	fileInfo os.FileInfo
+	store    *fileStore
+	// follow reports whether to read the lines added after EOF.
+	follow func() bool
+
+	endNum int
+	eof    bool
+	// partial is true if the last line does not end with a newline.
+	partial bool
+	// offset is the number of bytes read.
+	offset int64
+	notify func(n int)
+
+	readyCh   chan struct{}
+	readyOnce sync.Once
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu sync.Mutex
+}
+
+// NewFileSource returns a FileSource that reads the file in the background.
+// follow is called periodically after EOF, and while it returns true,
+// the lines added to the file are read (like tail -f).
+// follow can be nil.
+func NewFileSource(fileName string, follow func() bool) (*FileSource, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	store, err := newFileStore(fileName)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s := &FileSource{
+		fileName: fileName,
+		fileInfo: fi,
+		store:    store,
+		follow:   follow,
+		readyCh:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		err := readLines(bufio.NewReader(f), s, s.done, s.setReady)
+		f.Close()
+		if err != nil {
+			log.Printf("error: %v\n", err)
+		}
+		s.mu.Lock()
+		s.eof = err == nil
+		s.mu.Unlock()
+		s.setReady()
+		if s.follow != nil {
+			s.followFile()
+		}
+	}()
+	return s, nil
+}
+
+// Line returns the line n.
+func (s *FileSource) Line(n int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 || n >= s.endNum || s.closed() {
+		return ""
+	}
+	line, err := s.store.line(n, s.offset)
+	if err != nil {
+		log.Printf("%s: %s", s.fileName, err)
+	}
+	return line
+}
+
+// Len returns the number of lines read.
+func (s *FileSource) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.endNum
+}
+
+// EOF returns true if EOF is reached.
+func (s *FileSource) EOF() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eof
+}
+
+// Offset returns the byte offset of the beginning of the line n.
+func (s *FileSource) Offset(n int) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n >= s.endNum || s.closed() {
+		return s.offset
+	}
+	offset, err := s.store.lineOffset(max(n, 0), s.offset)
+	if err != nil {
+		log.Printf("%s: %s", s.fileName, err)
+	}
+	return offset
+}
+
+// LineAt returns the line that contains the byte offset.
+func (s *FileSource) LineAt(offset int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.endNum == 0 || s.closed() {
+		return 0
+	}
+	if offset >= s.offset {
+		return s.endNum - 1
+	}
+	n, err := s.store.offsetLine(offset, s.offset)
+	if err != nil {
+		log.Printf("%s: %s", s.fileName, err)
+	}
+	return n
+}
+
+// Size returns the number of bytes read.
+func (s *FileSource) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// SetNotify sets the function that is called when lines are modified.
+func (s *FileSource) SetNotify(f func(n int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = f
+}
+
+// Close stops reading and closes the file.
+func (s *FileSource) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		close(s.done)
+		s.store.close()
+	})
+	return nil
+}
+
+func (s *FileSource) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *FileSource) ready() <-chan struct{} {
+	return s.readyCh
+}
+
+func (s *FileSource) setReady() {
+	s.readyOnce.Do(func() {
+		close(s.readyCh)
+	})
+}
+
+// changed calls the notify function if it is set.
+func (s *FileSource) changed(n int) {
+	s.mu.Lock()
+	notify := s.notify
+	s.mu.Unlock()
+	if notify != nil {
+		notify(n)
+	}
+}
+
+// appendLine records the offset of the line.
+func (s *FileSource) appendLine(buf []byte, complete bool) {
+	s.mu.Lock()
+	s.offset += int64(len(buf))
+	if s.partial {
+		last := s.endNum - 1
+		s.partial = !complete
+		s.mu.Unlock()
+		s.changed(last)
+		return
+	}
+	s.store.addLine(s.endNum, s.offset-int64(len(buf)))
+	s.endNum++
+	s.partial = !complete
+	s.mu.Unlock()
+}
+
+// partialLine returns true if the last line does not end with a newline.
+func (s *FileSource) partialLine() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.partial
+}
+
+// followFile reads the lines added to the file after EOF.
+// When the file is truncated or rotated, it is reopened
+// and read from the beginning.
+func (s *FileSource) followFile() {
+	var f *os.File
+	var reader *bufio.Reader
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(followInterval):
+		}
+		if !s.follow() {
+			continue
+		}
+
+		fi, err := os.Stat(s.fileName)
+		if err != nil {
+			continue
+		}
+
+		truncated := fi.Size() < s.offset
+		rotated := !os.SameFile(s.fileInfo, fi)
+		if f == nil || truncated || rotated {
+			if f != nil {
+				f.Close()
+				f = nil
+			}
+			nf, err := os.Open(s.fileName)
+			if err != nil {
+				continue
+			}
+			f = nf
+			if truncated || rotated {
+				log.Printf("%s: file truncated or rotated", s.fileName)
+				s.reset(fi)
+			}
+			if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+				log.Printf("follow: %v", err)
+				continue
+			}
+			reader = bufio.NewReader(f)
+		}
+
+		if err := readLines(reader, s, s.done, nil); err != nil {
+			log.Printf("follow: %v", err)
+		}
+	}
+}
+
+// reset discards the lines to read the new file from the beginning.
+// The lines of the old file cannot be read anymore.
+func (s *FileSource) reset(fi os.FileInfo) {
+	s.mu.Lock()
+	s.fileInfo = fi
+	s.offset = 0
+	s.partial = false
+	s.endNum = 0
+	if err := s.store.reset(s.fileName); err != nil {
+		log.Printf("follow: %v", err)
+	}
+	s.mu.Unlock()
+	s.changed(0)
+}
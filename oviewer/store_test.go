@@ -0,0 +1,95 @@
+package oviewer
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileSource(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want []string
+	}{
+		{
+			name: "testChunks",
+			str:  strings.Repeat("foo\nbar\r\n", chunkLines+100),
+		},
+		{
+			name: "testPartial",
+			str:  "foo\nbar\nbaz",
+			want: []string{"foo", "bar", "baz"},
+		},
+		{
+			name: "testEmptyLines",
+			str:  "\n\nfoo\n",
+			want: []string{"", "", "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "ov-store")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.WriteString(tt.str); err != nil {
+				t.Fatal(err)
+			}
+			f.Close()
+
+			want := tt.want
+			if want == nil {
+				want = strings.Split(strings.ReplaceAll(strings.TrimSuffix(tt.str, "\n"), "\r", ""), "\n")
+			}
+
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.close()
+			if err := m.ReadFile(f.Name()); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 50 && !m.BufEOF(); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if _, ok := m.src.(*FileSource); !ok {
+				t.Fatalf("Document.src = %T, want *FileSource", m.src)
+			}
+			if got := m.BufEndNum(); got != len(want) {
+				t.Fatalf("Document.BufEndNum() = %d, want %d", got, len(want))
+			}
+			src := m.src.(*FileSource)
+			offset := int64(0)
+			for n, w := range want {
+				if got := m.GetLine(n); got != w {
+					t.Fatalf("Document.GetLine(%d) = %q, want %q", n, got, w)
+				}
+				if got := src.Offset(n); got != offset {
+					t.Fatalf("FileSource.Offset(%d) = %d, want %d", n, got, offset)
+				}
+				if got := src.LineAt(offset); got != n {
+					t.Fatalf("FileSource.LineAt(%d) = %d, want %d", offset, got, n)
+				}
+				offset += int64(strings.IndexByte(tt.str[offset:]+"\n", '\n') + 1)
+			}
+		})
+	}
+}
+
+func Test_splitLines(t *testing.T) {
+	got := splitLines([]byte("a\r\nb\n\nc"))
+	want := []string{"a", "b", "", "c"}
+	if !reflect.DeepEqual(got.lines, want) {
+		t.Errorf("splitLines() = %q, want %q", got.lines, want)
+	}
+	wantStarts := []int64{0, 3, 5, 6}
+	if !reflect.DeepEqual(got.starts, wantStarts) {
+		t.Errorf("splitLines() starts = %v, want %v", got.starts, wantStarts)
+	}
+}
@@ -0,0 +1,471 @@
+package oviewer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// SyntaxAuto chooses the syntax highlighting by the extension of the file name.
+const SyntaxAuto = "auto"
+
+// syntaxKind is the kind of a token of the syntax highlighting.
+type syntaxKind int
+
+const (
+	syntaxKeyword syntaxKind = iota
+	syntaxType
+	syntaxString
+	syntaxComment
+	syntaxNumber
+	syntaxKey
+	syntaxVariable
+	syntaxAdded
+	syntaxRemoved
+	syntaxMeta
+	syntaxHunk
+)
+
+// syntaxNames is the names of the kinds used in the theme of the config.
+var syntaxNames = map[string]syntaxKind{
+	"keyword":  syntaxKeyword,
+	"type":     syntaxType,
+	"string":   syntaxString,
+	"comment":  syntaxComment,
+	"number":   syntaxNumber,
+	"key":      syntaxKey,
+	"variable": syntaxVariable,
+	"added":    syntaxAdded,
+	"removed":  syntaxRemoved,
+	"meta":     syntaxMeta,
+	"hunk":     syntaxHunk,
+}
+
+// SyntaxStyles represents the styles of the kinds of the syntax highlighting.
+var SyntaxStyles = map[syntaxKind]tcell.Style{
+	syntaxKeyword:  tcell.StyleDefault.Foreground(tcell.ColorYellow),
+	syntaxType:     tcell.StyleDefault.Foreground(tcell.ColorAqua),
+	syntaxString:   tcell.StyleDefault.Foreground(tcell.ColorGreen),
+	syntaxComment:  tcell.StyleDefault.Foreground(tcell.ColorGray),
+	syntaxNumber:   tcell.StyleDefault.Foreground(tcell.ColorFuchsia),
+	syntaxKey:      tcell.StyleDefault.Foreground(tcell.ColorAqua),
+	syntaxVariable: tcell.StyleDefault.Foreground(tcell.ColorFuchsia),
+	syntaxAdded:    tcell.StyleDefault.Foreground(tcell.ColorGreen),
+	syntaxRemoved:  tcell.StyleDefault.Foreground(tcell.ColorRed),
+	syntaxMeta:     tcell.StyleDefault.Bold(true),
+	syntaxHunk:     tcell.StyleDefault.Foreground(tcell.ColorAqua),
+}
+
+// syntaxToken is the range of a token of the syntax highlighting.
+type syntaxToken struct {
+	start int
+	end   int
+	kind  syntaxKind
+}
+
+// lexer splits a line into the tokens.
+// state is the state at the start of the line, such as in a comment,
+// and the state at the start of the next line is returned.
+type lexer interface {
+	tokens(line string, state int) ([]syntaxToken, int)
+}
+
+// statelessLexer is a lexer whose tokens do not depend on the previous lines.
+// The state of the lines is not computed for it.
+type statelessLexer interface {
+	stateless()
+}
+
+// lexers is the lexers of the languages.
+var lexers = map[string]lexer{
+	"diff": diffLexer{},
+	"go":   goLexer,
+	"json": jsonLexer{},
+	"yaml": yamlLexer{},
+	"sh":   shLexer,
+}
+
+// syntaxAliases is the other names and the extensions of the languages.
+var syntaxAliases = map[string]string{
+	"patch": "diff",
+	"yml":   "yaml",
+	"bash":  "sh",
+	"zsh":   "sh",
+	"shell": "sh",
+}
+
+// lookupLexer returns the lexer of the name or the extension of the language.
+func lookupLexer(name string) (lexer, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := syntaxAliases[name]; ok {
+		name = alias
+	}
+	l, ok := lexers[name]
+	return l, ok
+}
+
+// lexer returns the lexer of the document.
+// It returns nil if the syntax highlighting is off.
+func (m *Document) lexer() lexer {
+	name := m.Syntax
+	if name == SyntaxAuto {
+		name = strings.TrimPrefix(filepath.Ext(m.syntaxFileName()), ".")
+	}
+	if name == "" {
+		return nil
+	}
+	l, ok := lookupLexer(name)
+	if !ok {
+		return nil
+	}
+	return l
+}
+
+// highlightSyntax applies the styles of the tokens to the contents.
+// byteMap maps the byte positions of the tokens to the contents.
+// The contents that already have a style by escape sequences are not changed.
+func highlightSyntax(lc lineContents, byteMap map[int]int, tokens []syntaxToken) {
+	for _, t := range tokens {
+		style := SyntaxStyles[t.kind]
+		for n := byteMap[t.start]; n < byteMap[t.end]; n++ {
+			if lc[n].style == tcell.StyleDefault {
+				lc[n].style = style
+			}
+		}
+	}
+}
+
+// syntaxStart returns the state of the lexer at the start of the line.
+// The states are computed from the first line,
+// because a comment or a string can continue over lines.
+// It returns false if the state is not computed yet.
+func (m *Document) syntaxStart(l lexer, lineNum int) (int, bool) {
+	if _, ok := l.(statelessLexer); ok {
+		return 0, true
+	}
+	next := func(n int, st interface{}) interface{} {
+		line := m.viewLine(n)
+		if strings.ContainsAny(line, "\x1b\b") {
+			line = stripEscapeSequence.ReplaceAllString(line, "")
+		}
+		_, next := l.tokens(line, st.(int))
+		return next
+	}
+	// The lines highlighted before the states are computed are parsed again.
+	ready := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.cache != nil {
+			m.cache.Clear()
+		}
+	}
+	st, ok := m.syntaxStates.start(l, 0, lineNum, m.BufEndNum, next, ready, m.done)
+	if !ok {
+		return 0, false
+	}
+	return st.(int), true
+}
+
+// resetSyntax discards the cached states after the line n.
+func (m *Document) resetSyntax(n int) {
+	m.syntaxStates.reset(n)
+}
+
+// diffLexer is the lexer of diff and patch.
+type diffLexer struct{}
+
+func (diffLexer) stateless() {}
+
+func (diffLexer) tokens(line string, state int) ([]syntaxToken, int) {
+	kind := syntaxKind(-1)
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+		strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		kind = syntaxMeta
+	case strings.HasPrefix(line, "@@"):
+		kind = syntaxHunk
+	case strings.HasPrefix(line, "+"), strings.HasPrefix(line, ">"):
+		kind = syntaxAdded
+	case strings.HasPrefix(line, "-"), strings.HasPrefix(line, "<"):
+		kind = syntaxRemoved
+	}
+	if kind < 0 || line == "" {
+		return nil, 0
+	}
+	return []syntaxToken{{start: 0, end: len(line), kind: kind}}, 0
+}
+
+// jsonLexer is the lexer of JSON.
+type jsonLexer struct{}
+
+func (jsonLexer) stateless() {}
+
+func (jsonLexer) tokens(line string, state int) ([]syntaxToken, int) {
+	return jsonTokens(line), 0
+}
+
+// yamlLexer is the lexer of YAML.
+type yamlLexer struct{}
+
+func (yamlLexer) stateless() {}
+
+func (yamlLexer) tokens(line string, state int) ([]syntaxToken, int) {
+	var tokens []syntaxToken
+	trimmed := strings.TrimLeft(line, " ")
+	i := len(line) - len(trimmed)
+	if strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "...") {
+		return []syntaxToken{{start: i, end: len(line), kind: syntaxMeta}}, 0
+	}
+	for strings.HasPrefix(line[i:], "- ") {
+		i += 2
+	}
+	// key
+	if k := yamlKeyEnd(line[i:]); k > 0 {
+		tokens = append(tokens, syntaxToken{start: i, end: i + k, kind: syntaxKey})
+		i += k + 1
+	}
+	for i < len(line) {
+		switch c := line[i]; {
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return append(tokens, syntaxToken{start: i, end: len(line), kind: syntaxComment}), 0
+		case c == '"' || c == '\'':
+			j := quotedEnd(line, i, c == '"')
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxString})
+			i = j
+		case c == ' ' || c == '[' || c == ']' || c == '{' || c == '}' || c == ',':
+			i++
+		default:
+			j := i
+			for j < len(line) && !strings.ContainsRune(" ,]}", rune(line[j])) {
+				j++
+			}
+			switch word := line[i:j]; {
+			case isNumber(word):
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxNumber})
+			case word == "true" || word == "false" || word == "null" || word == "~":
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxKeyword})
+			case word[0] == '&' || word[0] == '*' || word[0] == '!':
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxVariable})
+			}
+			i = j
+		}
+	}
+	return tokens, 0
+}
+
+// yamlKeyEnd returns the length of the key of the mapping at the start of s.
+// It returns 0 if s does not start with a key.
+func yamlKeyEnd(s string) int {
+	if s == "" || s[0] == '#' || s[0] == '"' || s[0] == '\'' {
+		return 0
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ':':
+			if i+1 == len(s) || s[i+1] == ' ' {
+				return i
+			}
+		case ' ':
+			if i+1 < len(s) && s[i+1] == '#' {
+				return 0
+			}
+		}
+	}
+	return 0
+}
+
+// cLexer is the lexer of the languages like C.
+type cLexer struct {
+	keywords map[string]bool
+	types    map[string]bool
+	// lineComment starts a comment until the end of the line.
+	lineComment string
+	// blockComment is true if /* */ is a comment.
+	blockComment bool
+	// rawQuote is the quote of the string that can continue over lines.
+	rawQuote byte
+	// quotes is the quotes of the strings that can contain the escaped quote.
+	quotes string
+	// literalQuotes is the quotes of the strings without escapes.
+	literalQuotes string
+	// variable is true if $name and ${name} are variables.
+	variable bool
+}
+
+// The states at the end of the line of cLexer.
+const (
+	cInBlockComment = 1
+	cInRawString    = 2
+)
+
+func (l *cLexer) tokens(line string, state int) ([]syntaxToken, int) {
+	var tokens []syntaxToken
+	i := 0
+	switch state {
+	case cInBlockComment:
+		j := strings.Index(line, "*/")
+		if j < 0 {
+			return []syntaxToken{{start: 0, end: len(line), kind: syntaxComment}}, state
+		}
+		tokens = append(tokens, syntaxToken{start: 0, end: j + 2, kind: syntaxComment})
+		i = j + 2
+	case cInRawString:
+		j := strings.IndexByte(line, l.rawQuote)
+		if j < 0 {
+			return []syntaxToken{{start: 0, end: len(line), kind: syntaxString}}, state
+		}
+		tokens = append(tokens, syntaxToken{start: 0, end: j + 1, kind: syntaxString})
+		i = j + 1
+	}
+
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case l.isLineComment(line, i):
+			return append(tokens, syntaxToken{start: i, end: len(line), kind: syntaxComment}), 0
+		case l.blockComment && strings.HasPrefix(line[i:], "/*"):
+			j := strings.Index(line[i+2:], "*/")
+			if j < 0 {
+				return append(tokens, syntaxToken{start: i, end: len(line), kind: syntaxComment}), cInBlockComment
+			}
+			tokens = append(tokens, syntaxToken{start: i, end: i + j + 4, kind: syntaxComment})
+			i += j + 4
+		case l.rawQuote != 0 && c == l.rawQuote:
+			j := strings.IndexByte(line[i+1:], l.rawQuote)
+			if j < 0 {
+				return append(tokens, syntaxToken{start: i, end: len(line), kind: syntaxString}), cInRawString
+			}
+			tokens = append(tokens, syntaxToken{start: i, end: i + j + 2, kind: syntaxString})
+			i += j + 2
+		case strings.IndexByte(l.quotes, c) >= 0:
+			j := quotedEnd(line, i, true)
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxString})
+			i = j
+		case strings.IndexByte(l.literalQuotes, c) >= 0:
+			j := quotedEnd(line, i, false)
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxString})
+			i = j
+		case l.variable && c == '$' && i+1 < len(line):
+			j := variableEnd(line, i)
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxVariable})
+			i = j
+		case '0' <= c && c <= '9':
+			j := i
+			for j < len(line) && (isWordChar(line[j]) || line[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxNumber})
+			i = j
+		case isWordChar(c):
+			j := i
+			for j < len(line) && isWordChar(line[j]) {
+				j++
+			}
+			switch word := line[i:j]; {
+			case l.keywords[word]:
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxKeyword})
+			case l.types[word]:
+				tokens = append(tokens, syntaxToken{start: i, end: j, kind: syntaxType})
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens, 0
+}
+
+// isLineComment returns true if the line comment starts at i.
+// "#" must be at the start of a word as in shell scripts.
+func (l *cLexer) isLineComment(line string, i int) bool {
+	if l.lineComment == "" || !strings.HasPrefix(line[i:], l.lineComment) {
+		return false
+	}
+	if l.lineComment != "#" {
+		return true
+	}
+	return i == 0 || line[i-1] == ' ' || line[i-1] == '\t'
+}
+
+// variableEnd returns the end of the variable that starts with "$" at i.
+func variableEnd(s string, i int) int {
+	j := i + 1
+	switch {
+	case s[j] == '{':
+		if k := strings.IndexByte(s[j:], '}'); k >= 0 {
+			return j + k + 1
+		}
+		return len(s)
+	case strings.IndexByte("?#@*!$-", s[j]) >= 0, '0' <= s[j] && s[j] <= '9':
+		return j + 1
+	}
+	for j < len(s) && isWordChar(s[j]) {
+		j++
+	}
+	return j
+}
+
+// goLexer is the lexer of Go.
+var goLexer = &cLexer{
+	keywords: wordSet("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var true false nil iota"),
+	types: wordSet("bool byte complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr " +
+		"append cap close complex copy delete imag len make new panic print println real recover"),
+	lineComment:  "//",
+	blockComment: true,
+	rawQuote:     '`',
+	quotes:       `"'`,
+}
+
+// shLexer is the lexer of shell scripts.
+var shLexer = &cLexer{
+	keywords:      wordSet("if then else elif fi case esac for while until do done in function select time return exit break continue local export readonly declare set unset shift source trap"),
+	types:         wordSet("echo printf cd test read eval exec"),
+	lineComment:   "#",
+	quotes:        `"`,
+	literalQuotes: "'",
+	variable:      true,
+}
+
+// wordSet returns the set of the words separated by spaces.
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// quotedEnd returns the end of the quoted string that starts at i.
+// A backslash escapes the next character if escape is true.
+func quotedEnd(s string, i int, escape bool) int {
+	q := s[i]
+	j := i + 1
+	for j < len(s) && s[j] != q {
+		if escape && s[j] == '\\' {
+			j++
+		}
+		j++
+	}
+	return min(j+1, len(s))
+}
+
+// isWordChar returns true if c is a character of an identifier.
+func isWordChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// isNumber returns true if the word is a number.
+func isNumber(word string) bool {
+	word = strings.TrimPrefix(word, "-")
+	if word == "" || word[0] < '0' || word[0] > '9' {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if !isWordChar(c) && c != '.' {
+			return false
+		}
+	}
+	return true
+}
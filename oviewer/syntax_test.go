@@ -0,0 +1,245 @@
+package oviewer
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func Test_lexerTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		lexer     lexer
+		line      string
+		state     int
+		want      []syntaxToken
+		wantState int
+	}{
+		{
+			name:  "diffAdded",
+			lexer: diffLexer{},
+			line:  "+added",
+			want:  []syntaxToken{{start: 0, end: 6, kind: syntaxAdded}},
+		},
+		{
+			name:  "diffHeader",
+			lexer: diffLexer{},
+			line:  "--- a/file",
+			want:  []syntaxToken{{start: 0, end: 10, kind: syntaxMeta}},
+		},
+		{
+			name:  "diffContext",
+			lexer: diffLexer{},
+			line:  " context",
+			want:  nil,
+		},
+		{
+			name:  "go",
+			lexer: goLexer,
+			line:  `func f() int { return 10 } // "c"`,
+			want: []syntaxToken{
+				{start: 0, end: 4, kind: syntaxKeyword},
+				{start: 9, end: 12, kind: syntaxType},
+				{start: 15, end: 21, kind: syntaxKeyword},
+				{start: 22, end: 24, kind: syntaxNumber},
+				{start: 27, end: 33, kind: syntaxComment},
+			},
+		},
+		{
+			name:  "goString",
+			lexer: goLexer,
+			line:  `s := "a\"//b"`,
+			want:  []syntaxToken{{start: 5, end: 13, kind: syntaxString}},
+		},
+		{
+			name:      "goBlockComment",
+			lexer:     goLexer,
+			line:      `x /* comment`,
+			want:      []syntaxToken{{start: 2, end: 12, kind: syntaxComment}},
+			wantState: cInBlockComment,
+		},
+		{
+			name:  "goBlockCommentEnd",
+			lexer: goLexer,
+			line:  `end */ var`,
+			state: cInBlockComment,
+			want: []syntaxToken{
+				{start: 0, end: 6, kind: syntaxComment},
+				{start: 7, end: 10, kind: syntaxKeyword},
+			},
+		},
+		{
+			name:      "goRawString",
+			lexer:     goLexer,
+			line:      "s := `raw",
+			want:      []syntaxToken{{start: 5, end: 9, kind: syntaxString}},
+			wantState: cInRawString,
+		},
+		{
+			name:  "json",
+			lexer: jsonLexer{},
+			line:  `{"a": true}`,
+			want: []syntaxToken{
+				{start: 1, end: 4, kind: syntaxKey},
+				{start: 6, end: 10, kind: syntaxKeyword},
+			},
+		},
+		{
+			name:  "yaml",
+			lexer: yamlLexer{},
+			line:  `- name: "ov" # comment`,
+			want: []syntaxToken{
+				{start: 2, end: 6, kind: syntaxKey},
+				{start: 8, end: 12, kind: syntaxString},
+				{start: 13, end: 22, kind: syntaxComment},
+			},
+		},
+		{
+			name:  "yamlValue",
+			lexer: yamlLexer{},
+			line:  `port: 8080`,
+			want: []syntaxToken{
+				{start: 0, end: 4, kind: syntaxKey},
+				{start: 6, end: 10, kind: syntaxNumber},
+			},
+		},
+		{
+			name:  "yamlURL",
+			lexer: yamlLexer{},
+			line:  `http://example.com`,
+			want:  nil,
+		},
+		{
+			name:  "sh",
+			lexer: shLexer,
+			line:  `if [ "$a" ]; then echo ${b}'$c' # x`,
+			want: []syntaxToken{
+				{start: 0, end: 2, kind: syntaxKeyword},
+				{start: 5, end: 9, kind: syntaxString},
+				{start: 13, end: 17, kind: syntaxKeyword},
+				{start: 18, end: 22, kind: syntaxType},
+				{start: 23, end: 27, kind: syntaxVariable},
+				{start: 27, end: 31, kind: syntaxString},
+				{start: 32, end: 35, kind: syntaxComment},
+			},
+		},
+		{
+			name:  "shNotComment",
+			lexer: shLexer,
+			line:  `echo a#b $#`,
+			want: []syntaxToken{
+				{start: 0, end: 4, kind: syntaxType},
+				{start: 9, end: 11, kind: syntaxVariable},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, state := tt.lexer.tokens(tt.line, tt.state)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lexer.tokens() = %v, want %v", got, tt.want)
+			}
+			if state != tt.wantState {
+				t.Errorf("lexer.tokens() state = %v, want %v", state, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestDocument_lexer(t *testing.T) {
+	tests := []struct {
+		name     string
+		syntax   string
+		fileName string
+		want     lexer
+	}{
+		{name: "off", syntax: "", fileName: "a.go", want: nil},
+		{name: "autoGo", syntax: SyntaxAuto, fileName: "a.go", want: goLexer},
+		{name: "autoPatch", syntax: SyntaxAuto, fileName: "a.PATCH", want: diffLexer{}},
+		{name: "autoBracket", syntax: SyntaxAuto, fileName: "a [b].yml", want: yamlLexer{}},
+		{name: "autoUnknown", syntax: SyntaxAuto, fileName: "a.txt", want: nil},
+		{name: "name", syntax: "shell", fileName: "a.txt", want: shLexer},
+		{name: "unknown", syntax: "cobol", fileName: "a.go", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewDocument()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.Syntax = tt.syntax
+			m.FileName = tt.fileName
+			if got := m.lexer(); got != tt.want {
+				t.Errorf("Document.lexer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_lexer_derived(t *testing.T) {
+	src, err := NewSourceDocument(NewSliceSource([]string{"a: 1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Syntax = SyntaxAuto
+	src.FileName = "a.yml"
+	m, err := NewFilterDocument(src, regexp.MustCompile("a"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.close()
+	if got := m.lexer(); got != (yamlLexer{}) {
+		t.Errorf("Document.lexer() = %v, want %v", got, yamlLexer{})
+	}
+}
+
+func TestDocument_syntaxStart(t *testing.T) {
+	lines := []string{
+		"package main",
+		"/* comment",
+		"   comment */",
+		"var s = `raw",
+		"raw`",
+	}
+	m, err := NewSourceDocument(NewSliceSource(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{0, 0, cInBlockComment, 0, cInRawString}
+	for n, w := range want {
+		if got, ok := m.syntaxStart(goLexer, n); !ok || got != w {
+			t.Errorf("Document.syntaxStart(%d) = %v, %t, want %v, true", n, got, ok, w)
+		}
+	}
+}
+
+func TestDocument_syntaxStart_stateless(t *testing.T) {
+	m, err := NewSourceDocument(NewSliceSource([]string{"+a", "-b", "c"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []lexer{diffLexer{}, jsonLexer{}, yamlLexer{}} {
+		if got, ok := m.syntaxStart(l, 2); !ok || got != 0 {
+			t.Errorf("Document.syntaxStart(%T) = %v, %t, want 0, true", l, got, ok)
+		}
+	}
+	if m.syntaxStates.checkpoints != nil {
+		t.Errorf("Document.syntaxStart() computed the states of a stateless lexer")
+	}
+}
+
+func Test_highlightSyntax(t *testing.T) {
+	lc := parseString("\x1b[31mif\x1b[0m if", 8)
+	str, byteMap := contentsToStr(lc)
+	tokens, _ := shLexer.tokens(str, 0)
+	highlightSyntax(lc, byteMap, tokens)
+	red := tcell.StyleDefault.Foreground(tcell.ColorMaroon)
+	if lc[0].style != red {
+		t.Errorf("highlightSyntax() style = %v, want %v", lc[0].style, red)
+	}
+	if lc[3].style != SyntaxStyles[syntaxKeyword] {
+		t.Errorf("highlightSyntax() style = %v, want %v", lc[3].style, SyntaxStyles[syntaxKeyword])
+	}
+}
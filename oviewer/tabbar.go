@@ -0,0 +1,105 @@
+package oviewer
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// TabBarTop displays the tab bar on the top row.
+	TabBarTop = "top"
+	// TabBarBottom displays the tab bar on the bottom row.
+	TabBarBottom = "bottom"
+)
+
+// tabWidth is the maximum width of a tab.
+const tabWidth = 24
+
+var (
+	// TabStyle represents the style of the tabs.
+	TabStyle = tcell.StyleDefault
+	// TabCurrentStyle represents the style of the tab of the current document.
+	TabCurrentStyle = tcell.StyleDefault.Reverse(true)
+)
+
+// tab is the position of a tab on the tab bar.
+type tab struct {
+	start int
+	end   int
+}
+
+// tabBarRow returns the row of the tab bar on the terminal.
+func (root *Root) tabBarRow() (int, bool) {
+	if root.terminal == nil {
+		return 0, false
+	}
+	switch root.TabBar {
+	case TabBarTop:
+		return 0, true
+	case TabBarBottom:
+		_, h := root.terminal.Size()
+		return h - 1, true
+	}
+	return 0, false
+}
+
+// tabName returns the name displayed on the tab.
+func tabName(n int, m *Document) string {
+	name := runewidth.Truncate(m.FileName, tabWidth, "…")
+	return fmt.Sprintf(" %d:%s ", n+1, name)
+}
+
+// tabBarDraw draws the names of the documents on the tab bar.
+// The tabs are scrolled so that the current document is visible.
+func (root *Root) tabBarDraw() {
+	y, ok := root.tabBarRow()
+	if !ok {
+		return
+	}
+	screen := root.terminal
+	width, _ := screen.Size()
+
+	root.tabs = root.tabs[:0]
+	x := 0
+	for n, m := range root.DocList {
+		w := runewidth.StringWidth(tabName(n, m))
+		root.tabs = append(root.tabs, tab{start: x, end: x + w})
+		x += w
+	}
+	shift := 0
+	if current := root.tabs[root.CurrentDoc]; current.end > width {
+		shift = current.end - width
+	}
+
+	for x := 0; x < width; x++ {
+		screen.SetContent(x, y, ' ', nil, TabStyle)
+	}
+	for n, m := range root.DocList {
+		root.tabs[n].start -= shift
+		root.tabs[n].end -= shift
+		style := TabStyle
+		if n == root.CurrentDoc {
+			style = TabCurrentStyle
+		}
+		lc := strToContents(tabName(n, m), -1)
+		for i, c := range lc {
+			x := root.tabs[n].start + i
+			if x < 0 || x >= width {
+				continue
+			}
+			screen.SetContent(x, y, c.mainc, c.combc, style)
+		}
+	}
+}
+
+// clickTab switches to the document of the tab at x.
+func (root *Root) clickTab(x int) {
+	for n, t := range root.tabs {
+		if x >= t.start && x < t.end {
+			root.switchDocument(n)
+			return
+		}
+	}
+}